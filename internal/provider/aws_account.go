@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// awsAccount caches the ID of the account the provider's credentials belong to.
+type awsAccount struct {
+	mu sync.Mutex
+	id string
+}
+
+// accountID returns the AWS account ID of the configured credentials. S3 Control requests
+// are addressed by account, so resources that do not set account_id fall back to this.
+// Failed lookups are not cached.
+func (c *ClientS3) accountID(ctx context.Context) (string, error) {
+	c.account.mu.Lock()
+	defer c.account.mu.Unlock()
+	if c.account.id != "" {
+		return c.account.id, nil
+	}
+	out, err := c.STSClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "", err
+	}
+	c.account.id = aws.ToString(out.Account)
+	return c.account.id, nil
+}
@@ -0,0 +1,105 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &awsCallerIdentityDataSource{}
+	_ datasource.DataSourceWithConfigure = &awsCallerIdentityDataSource{}
+)
+
+// NewAWSCallerIdentityDataSource is a helper function to simplify the provider implementation.
+func NewAWSCallerIdentityDataSource() datasource.DataSource {
+	return &awsCallerIdentityDataSource{}
+}
+
+// awsCallerIdentityDataSource reports who the provider's AWS credentials belong to.
+type awsCallerIdentityDataSource struct {
+	client *ClientS3
+}
+
+type awsCallerIdentityDataSourceModel struct {
+	ID        types.String `tfsdk:"id"`
+	AccountID types.String `tfsdk:"account_id"`
+	ARN       types.String `tfsdk:"arn"`
+	UserID    types.String `tfsdk:"user_id"`
+}
+
+// Metadata returns the data source type name.
+func (d *awsCallerIdentityDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_aws_caller_identity"
+}
+
+// Schema defines the schema for the data source.
+func (d *awsCallerIdentityDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Returns the account, ARN and user ID of the credentials the provider runs with.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Same as account_id.",
+			},
+			"account_id": schema.StringAttribute{
+				Computed: true,
+			},
+			"arn": schema.StringAttribute{
+				Computed:    true,
+				Description: "ARN of the calling user or assumed role session.",
+			},
+			"user_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Unique identifier of the calling entity.",
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *awsCallerIdentityDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	out, err := d.client.STSClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"unable to read caller identity",
+			"Could not call STS GetCallerIdentity: "+err.Error(),
+		)
+		return
+	}
+
+	state := awsCallerIdentityDataSourceModel{
+		ID:        types.StringPointerValue(out.Account),
+		AccountID: types.StringPointerValue(out.Account),
+		ARN:       types.StringPointerValue(out.Arn),
+		UserID:    types.StringPointerValue(out.UserId),
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *awsCallerIdentityDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// updateAWSTags moves a resource from its prior tags to the planned ones. IAM and KMS tag
+// APIs only add or overwrite, so keys that are no longer planned are removed with untag
+// first, then every planned tag is written with tag.
+func updateAWSTags(ctx context.Context, planned, prior types.Map, untag func(keys []string) error, tag func(tags map[string]string) error) error {
+	plannedTags := map[string]string{}
+	priorTags := map[string]string{}
+	if diags := planned.ElementsAs(ctx, &plannedTags, false); diags.HasError() {
+		return fmt.Errorf("reading planned tags: %v", diags)
+	}
+	if diags := prior.ElementsAs(ctx, &priorTags, false); diags.HasError() {
+		return fmt.Errorf("reading previous tags: %v", diags)
+	}
+	var removed []string
+	for key := range priorTags {
+		if _, ok := plannedTags[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	sort.Strings(removed)
+
+	if len(removed) > 0 {
+		if err := untag(removed); err != nil {
+			return err
+		}
+	}
+	if len(plannedTags) > 0 {
+		return tag(plannedTags)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestUpdateAWSTags(t *testing.T) {
+	tagMap := func(tags map[string]string) types.Map {
+		elements := map[string]attr.Value{}
+		for key, value := range tags {
+			elements[key] = types.StringValue(value)
+		}
+		return types.MapValueMust(types.StringType, elements)
+	}
+
+	var untagged []string
+	var tagged map[string]string
+	err := updateAWSTags(context.Background(),
+		tagMap(map[string]string{"team": "data", "env": "prod"}),
+		tagMap(map[string]string{"team": "web", "owner": "ops", "cost": "1"}),
+		func(keys []string) error { untagged = keys; return nil },
+		func(tags map[string]string) error { tagged = tags; return nil },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"cost", "owner"}; !reflect.DeepEqual(untagged, want) {
+		t.Errorf("untagged %v, want %v", untagged, want)
+	}
+	if want := map[string]string{"team": "data", "env": "prod"}; !reflect.DeepEqual(tagged, want) {
+		t.Errorf("tagged %v, want %v", tagged, want)
+	}
+
+	err = updateAWSTags(context.Background(), types.MapNull(types.StringType), types.MapNull(types.StringType),
+		func([]string) error { t.Error("untag called without prior tags"); return nil },
+		func(map[string]string) error { t.Error("tag called without planned tags"); return nil },
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
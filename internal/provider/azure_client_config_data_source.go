@@ -0,0 +1,160 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &azureClientConfigDataSource{}
+	_ datasource.DataSourceWithConfigure = &azureClientConfigDataSource{}
+)
+
+// azureManagementScope is the token scope for Azure Resource Manager.
+const azureManagementScope = "https://management.azure.com/.default"
+
+// NewAzureClientConfigDataSource is a helper function to simplify the provider implementation.
+func NewAzureClientConfigDataSource() datasource.DataSource {
+	return &azureClientConfigDataSource{}
+}
+
+// azureClientConfigDataSource reports who the provider's Azure credentials belong to.
+type azureClientConfigDataSource struct {
+	client *azureProviderStruct
+}
+
+type azureClientConfigDataSourceModel struct {
+	TenantID       types.String `tfsdk:"tenant_id"`
+	ObjectID       types.String `tfsdk:"object_id"`
+	ClientID       types.String `tfsdk:"client_id"`
+	SubscriptionID types.String `tfsdk:"subscription_id"`
+}
+
+// azureTokenClaims are the claims of an Entra ID access token the data source reads.
+type azureTokenClaims struct {
+	TenantID string `json:"tid"`
+	ObjectID string `json:"oid"`
+	// AppID is set by v1 tokens and AuthorizedParty by v2 tokens.
+	AppID           string `json:"appid"`
+	AuthorizedParty string `json:"azp"`
+}
+
+// Metadata returns the data source type name.
+func (d *azureClientConfigDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_azure_client_config"
+}
+
+// Schema defines the schema for the data source.
+func (d *azureClientConfigDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Returns the tenant, object and client IDs of the credentials the provider runs with.",
+		Attributes: map[string]schema.Attribute{
+			"tenant_id": schema.StringAttribute{
+				Computed: true,
+			},
+			"object_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Object ID of the user, service principal or managed identity. Use it as the principal of role assignments.",
+			},
+			"client_id": schema.StringAttribute{
+				Computed:    true,
+				Description: "Application (client) ID the token was issued to.",
+			},
+			"subscription_id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Subscription in use. Access tokens do not name one, so this defaults to the AZURE_SUBSCRIPTION_ID environment variable and is null if that is unset.",
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *azureClientConfigDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state azureClientConfigDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	token, err := d.client.azClient.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{azureManagementScope}})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"unable to read Azure client configuration",
+			"Could not get an access token: "+err.Error(),
+		)
+		return
+	}
+	claims, err := decodeAzureTokenClaims(token.Token)
+	if err != nil {
+		resp.Diagnostics.AddError("unable to read Azure client configuration", err.Error())
+		return
+	}
+
+	state.TenantID = types.StringValue(claims.TenantID)
+	state.ObjectID = types.StringValue(claims.ObjectID)
+	state.ClientID = types.StringValue(claims.AppID)
+	if claims.AppID == "" {
+		state.ClientID = types.StringValue(claims.AuthorizedParty)
+	}
+	if state.SubscriptionID.IsNull() {
+		if subscription := os.Getenv("AZURE_SUBSCRIPTION_ID"); subscription != "" {
+			state.SubscriptionID = types.StringValue(subscription)
+		}
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// decodeAzureTokenClaims reads the payload of a JWT access token. The signature is not
+// checked; the token came straight from Entra ID over TLS.
+func decodeAzureTokenClaims(token string) (azureTokenClaims, error) {
+	var claims azureTokenClaims
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, errors.New("access token is not a JWT")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return claims, fmt.Errorf("decoding access token payload: %w", err)
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, fmt.Errorf("decoding access token claims: %w", err)
+	}
+	if claims.TenantID == "" || claims.ObjectID == "" {
+		return claims, errors.New("access token carries no tenant or object ID")
+	}
+	return claims, nil
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *azureClientConfigDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*azureProviderStruct)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *azureProviderStruct, got: %T. Set cloud_provider = \"azure\" to use this data source.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
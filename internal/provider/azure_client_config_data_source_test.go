@@ -0,0 +1,25 @@
+package provider
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestDecodeAzureTokenClaims(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"tid":"tenant","oid":"object","azp":"client"}`))
+	claims, err := decodeAzureTokenClaims("header." + payload + ".signature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.TenantID != "tenant" || claims.ObjectID != "object" || claims.AuthorizedParty != "client" {
+		t.Errorf("claims = %+v", claims)
+	}
+
+	if _, err := decodeAzureTokenClaims("not-a-token"); err == nil {
+		t.Error("expected an error for a token without three parts")
+	}
+	empty := base64.RawURLEncoding.EncodeToString([]byte(`{}`))
+	if _, err := decodeAzureTokenClaims("header." + empty + ".signature"); err == nil {
+		t.Error("expected an error for a token without tenant and object IDs")
+	}
+}
@@ -2,43 +2,61 @@ package provider
 
 import (
 	"context"
-	
-	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
 )
 
+// storageAccountSettings are the kind, SKU and default access tier a storage account is
+// created with.
+type storageAccountSettings struct {
+	Kind armstorage.Kind
+	SKU  armstorage.SKUName
+	// AccessTier is empty for premium accounts, which have no default access tier.
+	AccessTier armstorage.AccessTier
+	Tags       map[string]*string
+}
 
-func createStorageAccount(ctx context.Context, resourceGroupName string, storageAccountName string, location string) (*armstorage.Account, error) {
+// defaultStorageAccountSettings are the settings xsynchco_az_storage creates accounts with.
+var defaultStorageAccountSettings = storageAccountSettings{
+	Kind:       armstorage.KindStorageV2,
+	SKU:        armstorage.SKUNameStandardLRS,
+	AccessTier: armstorage.AccessTierCool,
+}
 
-	
-	
-	pollerResp, err := accountsClient.BeginCreate(
+// createStorageAccount creates a storage account and waits for the deployment to finish.
+func createStorageAccount(ctx context.Context, client *armstorage.AccountsClient, resourceGroupName string, storageAccountName string, location string, settings storageAccountSettings) (*armstorage.Account, error) {
+	properties := &armstorage.AccountPropertiesCreateParameters{
+		Encryption: &armstorage.Encryption{
+			Services: &armstorage.EncryptionServices{
+				File: &armstorage.EncryptionService{
+					KeyType: to.Ptr(armstorage.KeyTypeAccount),
+					Enabled: to.Ptr(true),
+				},
+				Blob: &armstorage.EncryptionService{
+					KeyType: to.Ptr(armstorage.KeyTypeAccount),
+					Enabled: to.Ptr(true),
+				},
+			},
+			KeySource: to.Ptr(armstorage.KeySourceMicrosoftStorage),
+		},
+	}
+	if settings.AccessTier != "" {
+		properties.AccessTier = to.Ptr(settings.AccessTier)
+	}
+
+	pollerResp, err := client.BeginCreate(
 		ctx,
 		resourceGroupName,
 		storageAccountName,
 		armstorage.AccountCreateParameters{
-			Kind: to.Ptr(armstorage.KindStorageV2),
+			Kind: to.Ptr(settings.Kind),
 			SKU: &armstorage.SKU{
-				Name: to.Ptr(armstorage.SKUNameStandardLRS),
+				Name: to.Ptr(settings.SKU),
 			},
-			Location: to.Ptr(location),
-			Properties: &armstorage.AccountPropertiesCreateParameters{
-				AccessTier: to.Ptr(armstorage.AccessTierCool),
-				Encryption: &armstorage.Encryption{
-					Services: &armstorage.EncryptionServices{
-						File: &armstorage.EncryptionService{
-							KeyType: to.Ptr(armstorage.KeyTypeAccount),
-							Enabled: to.Ptr(true),
-						},
-						Blob: &armstorage.EncryptionService{
-							KeyType: to.Ptr(armstorage.KeyTypeAccount),
-							Enabled: to.Ptr(true),
-						},
-					},
-					KeySource: to.Ptr(armstorage.KeySourceMicrosoftStorage),
-				},
-			},
-			
+			Location:   to.Ptr(location),
+			Tags:       settings.Tags,
+			Properties: properties,
 		}, nil)
 	if err != nil {
 		return nil, err
@@ -47,7 +65,6 @@ func createStorageAccount(ctx context.Context, resourceGroupName string, storage
 	if err != nil {
 		return nil, err
 	}
-	
+
 	return &resp.Account, nil
 }
-
@@ -0,0 +1,549 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &azureStorageAccountResource{}
+	_ resource.ResourceWithConfigure   = &azureStorageAccountResource{}
+	_ resource.ResourceWithModifyPlan  = &azureStorageAccountResource{}
+	_ resource.ResourceWithImportState = &azureStorageAccountResource{}
+)
+
+// azureStorageAccountType is the ARM resource type of storage accounts.
+const azureStorageAccountType = "Microsoft.Storage/storageAccounts"
+
+// NewAzureStorageAccountResource is a helper function to simplify the provider implementation.
+func NewAzureStorageAccountResource() resource.Resource {
+	return &azureStorageAccountResource{}
+}
+
+// azureStorageAccountResource manages a single storage account, identified by its ARM ID.
+type azureStorageAccountResource struct {
+	client *azureProviderStruct
+}
+
+type azureStorageAccountResourceModel struct {
+	ID                   types.String `tfsdk:"id"`
+	Last_Updated         types.String `tfsdk:"last_updated"`
+	Name                 types.String `tfsdk:"name"`
+	ResourceGroupName    types.String `tfsdk:"resource_group_name"`
+	Location             types.String `tfsdk:"location"`
+	SubscriptionID       types.String `tfsdk:"subscription_id"`
+	AccountKind          types.String `tfsdk:"account_kind"`
+	AccountTier          types.String `tfsdk:"account_tier"`
+	ReplicationType      types.String `tfsdk:"replication_type"`
+	AccessTier           types.String `tfsdk:"access_tier"`
+	Tags                 types.Map    `tfsdk:"tags"`
+	PrimaryBlobEndpoint  types.String `tfsdk:"primary_blob_endpoint"`
+	PrimaryDFSEndpoint   types.String `tfsdk:"primary_dfs_endpoint"`
+	PrimaryFileEndpoint  types.String `tfsdk:"primary_file_endpoint"`
+	PrimaryQueueEndpoint types.String `tfsdk:"primary_queue_endpoint"`
+	PrimaryTableEndpoint types.String `tfsdk:"primary_table_endpoint"`
+	PrimaryWebEndpoint   types.String `tfsdk:"primary_web_endpoint"`
+}
+
+func (r *azureStorageAccountResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*azureProviderStruct)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *azureProviderStruct, got: %T. Set cloud_provider = \"azure\" to use this resource.", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *azureStorageAccountResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_azure_storage_account"
+}
+
+// Schema defines the schema for the resource.
+func (r *azureStorageAccountResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	computed := []planmodifier.String{
+		stringplanmodifier.UseStateForUnknown(),
+	}
+	replace := []planmodifier.String{
+		stringplanmodifier.UseStateForUnknown(),
+		stringplanmodifier.RequiresReplace(),
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Manages one Azure storage account. Import it by ARM resource ID or as subscription/resourceGroup/name.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				Description:   "ARM resource ID of the storage account.",
+				PlanModifiers: computed,
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"name": schema.StringAttribute{
+				Required:      true,
+				Description:   "Globally unique account name of 3 to 24 lowercase letters and digits.",
+				PlanModifiers: replace,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[a-z0-9]{3,24}$`), "must be 3 to 24 lowercase letters and digits"),
+				},
+			},
+			"resource_group_name": schema.StringAttribute{
+				Required:      true,
+				Description:   "Existing resource group that holds the account.",
+				PlanModifiers: replace,
+			},
+			"location": schema.StringAttribute{
+				Optional:      true,
+				Computed:      true,
+				Description:   "Azure region of the account. Defaults to the provider region.",
+				PlanModifiers: replace,
+			},
+			"subscription_id": schema.StringAttribute{
+				Optional:      true,
+				Computed:      true,
+				Description:   "Subscription of the account. Defaults to the AZURE_SUBSCRIPTION_ID environment variable.",
+				PlanModifiers: replace,
+			},
+			"account_kind": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(string(armstorage.KindStorageV2)),
+				Description: "StorageV2, BlobStorage, BlockBlobStorage or FileStorage. BlockBlobStorage and FileStorage require the Premium tier. BlobStorage can be upgraded to StorageV2 in place; other changes replace the account.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(azureStorageAccountKinds...),
+				},
+			},
+			"account_tier": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(azureTierStandard),
+				Description: "Standard or Premium. Changing it replaces the account.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(azureStorageAccountTiers...),
+				},
+			},
+			"replication_type": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("LRS"),
+				Description: "LRS, ZRS, GRS, RAGRS, GZRS or RAGZRS; Premium accounts support LRS and ZRS. Standard accounts switch among LRS, GRS and RAGRS, " +
+					"or between GZRS and RAGZRS, in place; other changes replace the account.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(azureReplicationTypes...),
+				},
+			},
+			"access_tier": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Default access tier for blobs: Hot, Cool or Cold. Defaults to Hot on Standard accounts; Premium accounts have none.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(azureAccessTiers...),
+				},
+			},
+			"tags": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"primary_blob_endpoint": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: computed,
+			},
+			"primary_dfs_endpoint": schema.StringAttribute{
+				Computed:      true,
+				Description:   "Data Lake Storage endpoint.",
+				PlanModifiers: computed,
+			},
+			"primary_file_endpoint": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: computed,
+			},
+			"primary_queue_endpoint": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: computed,
+			},
+			"primary_table_endpoint": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: computed,
+			},
+			"primary_web_endpoint": schema.StringAttribute{
+				Computed:      true,
+				Description:   "Static website endpoint.",
+				PlanModifiers: computed,
+			},
+		},
+	}
+}
+
+// ModifyPlan rejects account settings ARM does not offer, decides which setting changes
+// need a new account, and fills in the provider region, the subscription from the
+// environment and the default access tier so that they are known at plan time.
+func (r *azureStorageAccountResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan, config azureStorageAccountResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	attribute, err := checkAzureStorageAccountSettings(
+		plan.AccountKind.ValueString(),
+		plan.AccountTier.ValueString(),
+		plan.ReplicationType.ValueString(),
+		config.AccessTier.ValueString(),
+	)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root(attribute), "Unsupported Storage Account Settings", err.Error())
+		return
+	}
+
+	var state *azureStorageAccountResourceModel
+	if !req.State.Raw.IsNull() {
+		state = &azureStorageAccountResourceModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		for _, attribute := range azureStorageAccountReplacements(state.settings(), plan.settings()) {
+			resp.RequiresReplace = append(resp.RequiresReplace, path.Root(attribute))
+		}
+	}
+
+	// Premium accounts have no access tier and Standard accounts default to Hot. An account
+	// that keeps its tier keeps whatever access tier it has.
+	if config.AccessTier.IsNull() && !plan.AccountTier.IsUnknown() {
+		accessTier := types.StringValue(string(armstorage.AccessTierHot))
+		switch {
+		case plan.AccountTier.ValueString() == azureTierPremium:
+			accessTier = types.StringNull()
+		case state != nil && state.AccountTier.Equal(plan.AccountTier) && !state.AccessTier.IsNull():
+			accessTier = state.AccessTier
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("access_tier"), accessTier)...)
+	}
+
+	if r.client == nil {
+		return
+	}
+	if plan.Location.IsUnknown() && req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("location"), r.client.Region)...)
+	}
+	if plan.SubscriptionID.IsUnknown() && req.State.Raw.IsNull() {
+		subscription := os.Getenv("AZURE_SUBSCRIPTION_ID")
+		if subscription == "" {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("subscription_id"),
+				"Missing Subscription",
+				"Set subscription_id or the AZURE_SUBSCRIPTION_ID environment variable.",
+			)
+			return
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("subscription_id"), subscription)...)
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *azureStorageAccountResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan azureStorageAccountResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := armstorage.NewAccountsClient(plan.SubscriptionID.ValueString(), r.client.azClient, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating storage account client", err.Error())
+		return
+	}
+	tags := map[string]string{}
+	resp.Diagnostics.Append(plan.Tags.ElementsAs(ctx, &tags, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	settings := plan.settings()
+	settings.Tags = azureTags(tags)
+
+	account, err := createStorageAccount(ctx, client, plan.ResourceGroupName.ValueString(), plan.Name.ValueString(), plan.Location.ValueString(), settings)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating storage account",
+			"Could not create storage account "+plan.Name.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	setAzureStorageAccountState(&plan, account)
+	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags := resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *azureStorageAccountResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state azureStorageAccountResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := armstorage.NewAccountsClient(state.SubscriptionID.ValueString(), r.client.azClient, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating storage account client", err.Error())
+		return
+	}
+	out, err := client.GetProperties(ctx, state.ResourceGroupName.ValueString(), state.Name.ValueString(), nil)
+	if err != nil {
+		if isAzureNotFound(err) {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading storage account",
+			"Could not read storage account "+state.Name.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	setAzureStorageAccountState(&state, &out.Account)
+	tags := azureTagsToMap(out.Tags)
+	if len(tags) > 0 || !state.Tags.IsNull() {
+		tagMap, diags := types.MapValueFrom(ctx, types.StringType, tags)
+		resp.Diagnostics.Append(diags...)
+		state.Tags = tagMap
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *azureStorageAccountResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state azureStorageAccountResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := armstorage.NewAccountsClient(plan.SubscriptionID.ValueString(), r.client.azClient, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating storage account client", err.Error())
+		return
+	}
+	tags := map[string]string{}
+	resp.Diagnostics.Append(plan.Tags.ElementsAs(ctx, &tags, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The tags of an update replace the whole tag set, so an empty map clears them. ModifyPlan
+	// replaces the account for every other change ARM cannot make in place.
+	params := armstorage.AccountUpdateParameters{
+		Tags: azureTags(tags),
+	}
+	settings := plan.settings()
+	if !plan.AccountKind.Equal(state.AccountKind) {
+		params.Kind = to.Ptr(settings.Kind)
+	}
+	if !plan.AccountTier.Equal(state.AccountTier) || !plan.ReplicationType.Equal(state.ReplicationType) {
+		params.SKU = &armstorage.SKU{Name: to.Ptr(settings.SKU)}
+	}
+	// ARM requires the access tier with every kind change, as when BlobStorage is upgraded
+	// to StorageV2, even if the tier itself stays the same.
+	accessTier := settings.AccessTier
+	if accessTier == "" && params.Kind != nil {
+		accessTier = armstorage.AccessTier(state.AccessTier.ValueString())
+	}
+	if accessTier != "" && (params.Kind != nil || !plan.AccessTier.Equal(state.AccessTier)) {
+		params.Properties = &armstorage.AccountPropertiesUpdateParameters{AccessTier: to.Ptr(accessTier)}
+	}
+	out, err := client.Update(ctx, plan.ResourceGroupName.ValueString(), plan.Name.ValueString(), params, nil)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating storage account",
+			"Could not update storage account "+plan.Name.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	setAzureStorageAccountState(&plan, &out.Account)
+	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags := resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *azureStorageAccountResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state azureStorageAccountResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := armstorage.NewAccountsClient(state.SubscriptionID.ValueString(), r.client.azClient, nil)
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating storage account client", err.Error())
+		return
+	}
+	_, err = client.Delete(ctx, state.ResourceGroupName.ValueString(), state.Name.ValueString(), nil)
+	if err != nil && !isAzureNotFound(err) {
+		resp.Diagnostics.AddError(
+			"Error deleting storage account",
+			"Could not delete storage account "+state.Name.ValueString()+": "+err.Error(),
+		)
+	}
+}
+
+// ImportState imports an account by its ARM resource ID or as subscription/resourceGroup/name.
+func (r *azureStorageAccountResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	subscription, resourceGroup, name, err := parseAzureStorageAccountID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), azureStorageAccountID(subscription, resourceGroup, name))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("subscription_id"), subscription)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("resource_group_name"), resourceGroup)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+}
+
+// settings returns the kind, SKU and access tier of the model, leaving out unknown values.
+func (m azureStorageAccountResourceModel) settings() storageAccountSettings {
+	var settings storageAccountSettings
+	if !m.AccountKind.IsUnknown() {
+		settings.Kind = armstorage.Kind(m.AccountKind.ValueString())
+	}
+	if !m.AccountTier.IsUnknown() && !m.ReplicationType.IsUnknown() {
+		settings.SKU = azureStorageAccountSKU(m.AccountTier.ValueString(), m.ReplicationType.ValueString())
+	}
+	if !m.AccessTier.IsUnknown() {
+		settings.AccessTier = armstorage.AccessTier(m.AccessTier.ValueString())
+	}
+	return settings
+}
+
+// setAzureStorageAccountState copies the ID, location, settings and primary endpoints of
+// account into model. Tags are left to the caller, which knows whether they were configured.
+func setAzureStorageAccountState(model *azureStorageAccountResourceModel, account *armstorage.Account) {
+	model.ID = types.StringPointerValue(account.ID)
+	// ARM reports the location as a name like eastus; keep a configured display name like
+	// "East US" that refers to the same region.
+	if location := types.StringPointerValue(account.Location); azureLocationName(model.Location.ValueString()) != location.ValueString() {
+		model.Location = location
+	}
+
+	if account.Kind != nil {
+		model.AccountKind = types.StringValue(string(*account.Kind))
+	}
+	if account.SKU != nil && account.SKU.Name != nil {
+		tier, replication := splitAzureStorageAccountSKU(*account.SKU.Name)
+		model.AccountTier = types.StringValue(tier)
+		model.ReplicationType = types.StringValue(replication)
+	}
+	// Premium block blob accounts report a Premium access tier, which is not a setting.
+	model.AccessTier = types.StringNull()
+	if account.Properties != nil && account.Properties.AccessTier != nil && *account.Properties.AccessTier != armstorage.AccessTierPremium {
+		model.AccessTier = types.StringValue(string(*account.Properties.AccessTier))
+	}
+
+	var endpoints armstorage.Endpoints
+	if account.Properties != nil && account.Properties.PrimaryEndpoints != nil {
+		endpoints = *account.Properties.PrimaryEndpoints
+	}
+	model.PrimaryBlobEndpoint = types.StringPointerValue(endpoints.Blob)
+	model.PrimaryDFSEndpoint = types.StringPointerValue(endpoints.Dfs)
+	model.PrimaryFileEndpoint = types.StringPointerValue(endpoints.File)
+	model.PrimaryQueueEndpoint = types.StringPointerValue(endpoints.Queue)
+	model.PrimaryTableEndpoint = types.StringPointerValue(endpoints.Table)
+	model.PrimaryWebEndpoint = types.StringPointerValue(endpoints.Web)
+}
+
+// azureStorageAccountID builds the ARM resource ID of a storage account.
+func azureStorageAccountID(subscription, resourceGroup, name string) string {
+	return "/subscriptions/" + subscription + "/resourceGroups/" + resourceGroup + "/providers/" + azureStorageAccountType + "/" + name
+}
+
+// parseAzureStorageAccountID splits an ARM storage account ID, or the short form
+// subscription/resourceGroup/name, into its parts.
+func parseAzureStorageAccountID(id string) (subscription, resourceGroup, name string, err error) {
+	if !strings.HasPrefix(id, "/") {
+		parts := strings.Split(id, "/")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return "", "", "", fmt.Errorf("expected an ARM resource ID or subscription/resourceGroup/name, got %q", id)
+		}
+		return parts[0], parts[1], parts[2], nil
+	}
+
+	resourceID, err := arm.ParseResourceID(id)
+	if err != nil {
+		return "", "", "", fmt.Errorf("parsing resource ID %q: %w", id, err)
+	}
+	if !strings.EqualFold(resourceID.ResourceType.String(), azureStorageAccountType) {
+		return "", "", "", fmt.Errorf("resource ID %q is a %s, not a storage account", id, resourceID.ResourceType.String())
+	}
+	return resourceID.SubscriptionID, resourceID.ResourceGroupName, resourceID.Name, nil
+}
+
+// azureLocationName turns a region display name like "East US" into its name, eastus.
+func azureLocationName(location string) string {
+	return strings.ToLower(strings.ReplaceAll(location, " ", ""))
+}
+
+// isAzureNotFound reports whether err is an ARM response with status 404.
+func isAzureNotFound(err error) bool {
+	var responseErr *azcore.ResponseError
+	return errors.As(err, &responseErr) && responseErr.StatusCode == http.StatusNotFound
+}
+
+// azureTags converts tags to the pointer map the ARM SDK expects.
+func azureTags(tags map[string]string) map[string]*string {
+	converted := make(map[string]*string, len(tags))
+	for key, value := range tags {
+		converted[key] = &value
+	}
+	return converted
+}
+
+// azureTagsToMap converts ARM tags back to plain strings.
+func azureTagsToMap(tags map[string]*string) map[string]string {
+	converted := make(map[string]string, len(tags))
+	for key, value := range tags {
+		if value != nil {
+			converted[key] = *value
+		}
+	}
+	return converted
+}
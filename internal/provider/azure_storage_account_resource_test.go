@@ -0,0 +1,40 @@
+package provider
+
+import "testing"
+
+func TestParseAzureStorageAccountID(t *testing.T) {
+	const full = "/subscriptions/0000-1111/resourceGroups/data-rg/providers/Microsoft.Storage/storageAccounts/trainingdata"
+
+	cases := []struct {
+		name    string
+		id      string
+		wantErr bool
+	}{
+		{"arm id", full, false},
+		{"short form", "0000-1111/data-rg/trainingdata", false},
+		{"lower case provider", "/subscriptions/0000-1111/resourceGroups/data-rg/providers/microsoft.storage/storageaccounts/trainingdata", false},
+		{"missing part", "0000-1111/trainingdata", true},
+		{"empty part", "0000-1111//trainingdata", true},
+		{"other resource type", "/subscriptions/0000-1111/resourceGroups/data-rg/providers/Microsoft.Network/virtualNetworks/trainingdata", true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			subscription, resourceGroup, name, err := parseAzureStorageAccountID(tc.id)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error for %q", tc.id)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if subscription != "0000-1111" || resourceGroup != "data-rg" || name != "trainingdata" {
+				t.Errorf("got %s/%s/%s", subscription, resourceGroup, name)
+			}
+			if got := azureStorageAccountID(subscription, resourceGroup, name); got != full {
+				t.Errorf("azureStorageAccountID = %q, want %q", got, full)
+			}
+		})
+	}
+}
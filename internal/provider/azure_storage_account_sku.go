@@ -0,0 +1,99 @@
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+)
+
+const (
+	azureTierStandard = "Standard"
+	azureTierPremium  = "Premium"
+)
+
+var (
+	azureStorageAccountKinds = []string{
+		string(armstorage.KindStorageV2),
+		string(armstorage.KindBlobStorage),
+		string(armstorage.KindBlockBlobStorage),
+		string(armstorage.KindFileStorage),
+	}
+	azureStorageAccountTiers = []string{azureTierStandard, azureTierPremium}
+	azureReplicationTypes    = []string{"LRS", "ZRS", "GRS", "RAGRS", "GZRS", "RAGZRS"}
+	azureAccessTiers         = []string{
+		string(armstorage.AccessTierHot),
+		string(armstorage.AccessTierCool),
+		string(armstorage.AccessTierCold),
+	}
+)
+
+// azureStorageAccountSKU joins a tier and a replication type into an SKU name such as
+// Standard_RAGRS.
+func azureStorageAccountSKU(tier, replication string) armstorage.SKUName {
+	return armstorage.SKUName(tier + "_" + replication)
+}
+
+// splitAzureStorageAccountSKU is the inverse of azureStorageAccountSKU.
+func splitAzureStorageAccountSKU(sku armstorage.SKUName) (tier, replication string) {
+	tier, replication, _ = strings.Cut(string(sku), "_")
+	return tier, replication
+}
+
+// checkAzureStorageAccountSettings rejects combinations of kind, tier, replication and access
+// tier that ARM does not offer, returning the attribute at fault. Empty values are not known
+// yet and are not checked.
+func checkAzureStorageAccountSettings(kind, tier, replication, accessTier string) (string, error) {
+	switch {
+	case (kind == string(armstorage.KindBlockBlobStorage) || kind == string(armstorage.KindFileStorage)) && tier == azureTierStandard:
+		return "account_tier", fmt.Errorf("%s accounts require the Premium tier", kind)
+	case kind == string(armstorage.KindBlobStorage) && tier == azureTierPremium:
+		return "account_tier", fmt.Errorf("%s accounts require the Standard tier", kind)
+	case tier == azureTierPremium && replication != "" && replication != "LRS" && replication != "ZRS":
+		return "replication_type", fmt.Errorf("Premium accounts only support LRS and ZRS replication, not %s", replication)
+	case kind == string(armstorage.KindBlobStorage) && !azureReplicationWithoutZones(replication):
+		return "replication_type", fmt.Errorf("%s accounts only support LRS, GRS and RAGRS replication, not %s", kind, replication)
+	case tier == azureTierPremium && accessTier != "":
+		return "access_tier", fmt.Errorf("Premium accounts have no default access tier")
+	}
+	return "", nil
+}
+
+// azureStorageAccountReplacements lists the settings whose change ARM cannot apply to an
+// existing account. Kinds can only be upgraded to StorageV2, and the SKU can only move
+// between replication types within the same zone layout: ZRS and premium SKUs never change.
+func azureStorageAccountReplacements(prior, planned storageAccountSettings) []string {
+	var replace []string
+	if planned.Kind != "" && planned.Kind != prior.Kind &&
+		!(prior.Kind == armstorage.KindBlobStorage && planned.Kind == armstorage.KindStorageV2) {
+		replace = append(replace, "account_kind")
+	}
+
+	priorTier, priorReplication := splitAzureStorageAccountSKU(prior.SKU)
+	plannedTier, plannedReplication := splitAzureStorageAccountSKU(planned.SKU)
+	if plannedTier != "" && plannedTier != priorTier {
+		replace = append(replace, "account_tier")
+	}
+	if plannedReplication != "" && plannedReplication != priorReplication && !azureReplicationUpdatable(plannedTier, priorReplication, plannedReplication) {
+		replace = append(replace, "replication_type")
+	}
+	return replace
+}
+
+// azureReplicationUpdatable reports whether a Standard account can switch replication in
+// place, which ARM allows among LRS, GRS and RAGRS and between GZRS and RAGZRS.
+func azureReplicationUpdatable(tier, prior, planned string) bool {
+	if tier != azureTierStandard {
+		return false
+	}
+	if azureReplicationWithoutZones(prior) && azureReplicationWithoutZones(planned) {
+		return true
+	}
+	return (prior == "GZRS" || prior == "RAGZRS") && (planned == "GZRS" || planned == "RAGZRS")
+}
+
+// azureReplicationWithoutZones reports whether replication keeps the primary copies in a
+// single datacenter. An unknown replication type counts as such.
+func azureReplicationWithoutZones(replication string) bool {
+	return replication == "" || replication == "LRS" || replication == "GRS" || replication == "RAGRS"
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/storage/armstorage"
+)
+
+func TestCheckAzureStorageAccountSettings(t *testing.T) {
+	cases := []struct {
+		kind, tier, replication, accessTier string
+		wantAttribute                       string
+	}{
+		{"StorageV2", "Standard", "RAGZRS", "Cold", ""},
+		{"FileStorage", "Premium", "ZRS", "", ""},
+		{"BlockBlobStorage", "Premium", "LRS", "", ""},
+		{"FileStorage", "Standard", "LRS", "", "account_tier"},
+		{"BlobStorage", "Premium", "LRS", "", "account_tier"},
+		{"StorageV2", "Premium", "GRS", "", "replication_type"},
+		{"BlobStorage", "Standard", "ZRS", "Hot", "replication_type"},
+		{"StorageV2", "Premium", "LRS", "Hot", "access_tier"},
+		// Unknown values are not checked.
+		{"FileStorage", "", "", "", ""},
+	}
+	for _, tc := range cases {
+		attribute, err := checkAzureStorageAccountSettings(tc.kind, tc.tier, tc.replication, tc.accessTier)
+		if attribute != tc.wantAttribute || (err != nil) != (tc.wantAttribute != "") {
+			t.Errorf("%s %s_%s %q: got %q, %v, want %q", tc.kind, tc.tier, tc.replication, tc.accessTier, attribute, err, tc.wantAttribute)
+		}
+	}
+}
+
+func TestAzureStorageAccountReplacements(t *testing.T) {
+	settings := func(kind armstorage.Kind, sku armstorage.SKUName) storageAccountSettings {
+		return storageAccountSettings{Kind: kind, SKU: sku}
+	}
+	v2, blob := armstorage.KindStorageV2, armstorage.KindBlobStorage
+
+	cases := []struct {
+		name           string
+		prior, planned storageAccountSettings
+		want           []string
+	}{
+		{"no change", settings(v2, "Standard_LRS"), settings(v2, "Standard_LRS"), nil},
+		{"geo replication", settings(v2, "Standard_LRS"), settings(v2, "Standard_RAGRS"), nil},
+		{"zone redundant geo replication", settings(v2, "Standard_GZRS"), settings(v2, "Standard_RAGZRS"), nil},
+		{"upgrade to StorageV2", settings(blob, "Standard_GRS"), settings(v2, "Standard_GRS"), nil},
+		{"into zones", settings(v2, "Standard_LRS"), settings(v2, "Standard_ZRS"), []string{"replication_type"}},
+		{"out of ZRS", settings(v2, "Standard_ZRS"), settings(v2, "Standard_GZRS"), []string{"replication_type"}},
+		{"premium replication", settings(armstorage.KindFileStorage, "Premium_LRS"), settings(armstorage.KindFileStorage, "Premium_ZRS"), []string{"replication_type"}},
+		{"tier", settings(v2, "Standard_LRS"), settings(v2, "Premium_LRS"), []string{"account_tier"}},
+		{"downgrade kind", settings(v2, "Standard_LRS"), settings(blob, "Standard_LRS"), []string{"account_kind"}},
+		{"unknown", settings(v2, "Standard_LRS"), storageAccountSettings{}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := azureStorageAccountReplacements(tc.prior, tc.planned); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
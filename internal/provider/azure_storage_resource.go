@@ -86,6 +86,8 @@ func (r *azureStorageResource) Metadata(_ context.Context, req resource.Metadata
 // Schema defines the schema for the resource.
 func (r *azureStorageResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Description: "Creates an Azure storage account that is always a StorageV2 account with Standard_LRS replication and the Cool access tier. " +
+			"Use xsynchco_azure_storage_account to choose the kind, SKU or access tier.",
 		Attributes: map[string]schema.Attribute{
 			"subscriptionid": schema.StringAttribute{
 				Required: true,
@@ -160,7 +162,7 @@ func (r *azureStorageResource) Create(ctx context.Context, req resource.CreateRe
 	for index, item := range plan.StorageAccount {
 
 
-		storageResponse, err := createStorageAccount(context.Background(),plan.ResourceGroupName.ValueString(),item.Name.ValueString(),r.client.Region)
+		storageResponse, err := createStorageAccount(context.Background(),accountsClient,plan.ResourceGroupName.ValueString(),item.Name.ValueString(),r.client.Region,defaultStorageAccountSettings)
 
 		if err != nil {
 
@@ -3,9 +3,14 @@ package provider
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	hashitypes "github.com/hashicorp/terraform-plugin-framework/types"
 )
 
@@ -15,12 +20,12 @@ var (
 	_ datasource.DataSourceWithConfigure = &xsynchcoAWSDataSource{}
 )
 
-// NewCoffeesDataSource is a helper function to simplify the provider implementation.
+// NewXsynchcoAWSDataSource is a helper function to simplify the provider implementation.
 func NewXsynchcoAWSDataSource() datasource.DataSource {
 	return &xsynchcoAWSDataSource{}
 }
 
-// xsynchcoAWSDataSource is the data source implementation.
+// xsynchcoAWSDataSource lists the buckets of the account, optionally filtered.
 type xsynchcoAWSDataSource struct {
 	client *ClientS3
 }
@@ -28,12 +33,19 @@ type xsynchcoAWSDataSource struct {
 type bucketModel struct {
 	Date        hashitypes.String `tfsdk:"date"`
 	Name        hashitypes.String `tfsdk:"name"`
-	Tags        []string          `tfsdk:"tags"`
-	Description string            `tfsdk:"description"`
+	Region      hashitypes.String `tfsdk:"region"`
+	ARN         hashitypes.String `tfsdk:"arn"`
+	Tags        map[string]string `tfsdk:"tags"`
+	Description hashitypes.String `tfsdk:"description"`
 }
 
 type awsBucketDataSourceModel struct {
-	Buckets []bucketModel `tfsdk:"s3bucket"`
+	NameRegex               hashitypes.String `tfsdk:"name_regex"`
+	Prefix                  hashitypes.String `tfsdk:"prefix"`
+	Region                  hashitypes.String `tfsdk:"region"`
+	Tags                    map[string]string `tfsdk:"tags"`
+	IncludeDirectoryBuckets hashitypes.Bool   `tfsdk:"include_directory_buckets"`
+	Buckets                 []bucketModel     `tfsdk:"s3bucket"`
 }
 
 // Metadata returns the data source type name.
@@ -44,23 +56,55 @@ func (d *xsynchcoAWSDataSource) Metadata(_ context.Context, req datasource.Metad
 // Schema defines the schema for the data source.
 func (d *xsynchcoAWSDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Description: "Lists the S3 buckets of the account with their region, ARN and tags.",
 		Attributes: map[string]schema.Attribute{
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return buckets whose name matches this regular expression.",
+			},
+			"prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return buckets whose name starts with this prefix.",
+			},
+			"region": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only return buckets in this region.",
+			},
+			"tags": schema.MapAttribute{
+				Optional:    true,
+				ElementType: hashitypes.StringType,
+				Description: "Only return buckets carrying all of these tags with these values.",
+			},
+			"include_directory_buckets": schema.BoolAttribute{
+				Optional: true,
+				Description: "Also return the S3 Express One Zone directory buckets of the provider region. " +
+					"Directory buckets have no tags, so they are left out when tags is set.",
+			},
 			"s3bucket": schema.ListNestedAttribute{
 				Computed: true,
 				NestedObject: schema.NestedAttributeObject{
 					Attributes: map[string]schema.Attribute{
 						"date": schema.StringAttribute{
-							Computed: true,
+							Computed:    true,
+							Description: "Creation date of the bucket.",
 						},
 						"name": schema.StringAttribute{
 							Computed: true,
 						},
-						"tags": schema.StringAttribute{
+						"region": schema.StringAttribute{
 							Computed: true,
 						},
-						"description": schema.StringAttribute{
+						"arn": schema.StringAttribute{
 							Computed: true,
 						},
+						"tags": schema.MapAttribute{
+							Computed:    true,
+							ElementType: hashitypes.StringType,
+						},
+						"description": schema.StringAttribute{
+							Computed:    true,
+							Description: "Value of the bucket's Description tag, if any.",
+						},
 					},
 				},
 			},
@@ -71,30 +115,160 @@ func (d *xsynchcoAWSDataSource) Schema(_ context.Context, _ datasource.SchemaReq
 // Read refreshes the Terraform state with the latest data.
 func (d *xsynchcoAWSDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
 	var state awsBucketDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var nameRegex *regexp.Regexp
+	if !state.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(state.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid Regular Expression", err.Error())
+			return
+		}
+	}
+
+	input := &s3.ListBucketsInput{
+		Prefix:       state.Prefix.ValueStringPointer(),
+		BucketRegion: state.Region.ValueStringPointer(),
+	}
+	// Without a page size ListBuckets returns everything in one response and never
+	// hands out a continuation token.
+	paginator := s3.NewListBucketsPaginator(d.client.S3Client, input, func(o *s3.ListBucketsPaginatorOptions) {
+		o.Limit = 1000
+	})
+
+	state.Buckets = []bucketModel{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"unable to read bucket data",
+				"Could not list buckets: "+err.Error(),
+			)
+			return
+		}
+		for _, bucket := range page.Buckets {
+			name := aws.ToString(bucket.Name)
+			if nameRegex != nil && !nameRegex.MatchString(name) {
+				continue
+			}
+			region := aws.ToString(bucket.BucketRegion)
+			state.Buckets = append(state.Buckets, bucketModel{
+				Date:        hashitypes.StringValue(aws.ToTime(bucket.CreationDate).Format("2006-01-02 15:04:05")),
+				Name:        hashitypes.StringValue(name),
+				Region:      hashitypes.StringValue(region),
+				ARN:         hashitypes.StringValue(s3BucketARN(name, region)),
+				Description: hashitypes.StringNull(),
+			})
+		}
+	}
 
-	buckets, err := d.client.S3Client.ListBuckets(context.Background(), nil)
+	// Directory buckets are only listed by ListDirectoryBuckets, which covers the client's
+	// region and does not filter by prefix.
+	var directoryBuckets []bucketModel
+	region := d.client.S3Client.Options().Region
+	if state.IncludeDirectoryBuckets.ValueBool() && (state.Region.IsNull() || state.Region.ValueString() == region) {
+		accountID, err := d.client.accountID(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("unable to read bucket data", "Could not determine the account ID: "+err.Error())
+			return
+		}
+		paginator := s3.NewListDirectoryBucketsPaginator(d.client.S3Client, &s3.ListDirectoryBucketsInput{})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"unable to read bucket data",
+					"Could not list directory buckets: "+err.Error(),
+				)
+				return
+			}
+			for _, bucket := range page.Buckets {
+				name := aws.ToString(bucket.Name)
+				if !strings.HasPrefix(name, state.Prefix.ValueString()) || (nameRegex != nil && !nameRegex.MatchString(name)) {
+					continue
+				}
+				directoryBuckets = append(directoryBuckets, bucketModel{
+					Date:        hashitypes.StringValue(aws.ToTime(bucket.CreationDate).Format("2006-01-02 15:04:05")),
+					Name:        hashitypes.StringValue(name),
+					Region:      hashitypes.StringValue(region),
+					ARN:         hashitypes.StringValue(s3DirectoryBucketARN(name, region, accountID)),
+					Tags:        map[string]string{},
+					Description: hashitypes.StringNull(),
+				})
+			}
+		}
+	}
+
+	indexes := make([]int, len(state.Buckets))
+	for index := range indexes {
+		indexes[index] = index
+	}
+	err := forEachConcurrently(ctx, 10, indexes, func(ctx context.Context, index int) error {
+		bucket := &state.Buckets[index]
+		tags, err := readS3BucketTags(ctx, d.client.S3Client, bucket.Name.ValueString(), bucket.Region.ValueString())
+		if err != nil {
+			return fmt.Errorf("%s: %w", bucket.Name.ValueString(), err)
+		}
+		bucket.Tags = tags
+		if description, ok := tags["Description"]; ok {
+			bucket.Description = hashitypes.StringValue(description)
+		}
+		return nil
+	})
 	if err != nil {
-		resp.Diagnostics.AddError(
-			"unable to read bucket data",
-			err.Error(),
-		)
+		resp.Diagnostics.AddError("unable to read bucket tags", err.Error())
 		return
 	}
 
-	for _, bucket := range buckets.Buckets {
-		bucketState := bucketModel{
-			Date: hashitypes.StringValue(bucket.CreationDate.Format("2006-01-02 15:04:05")),
-			Name: hashitypes.StringValue(*bucket.Name),
+	state.Buckets = append(state.Buckets, directoryBuckets...)
+
+	if len(state.Tags) > 0 {
+		matching := state.Buckets[:0]
+		for _, bucket := range state.Buckets {
+			if hasS3Tags(bucket.Tags, state.Tags) {
+				matching = append(matching, bucket)
+			}
 		}
-		state.Buckets = append(state.Buckets, bucketState)
+		state.Buckets = matching
 	}
 
 	diags := resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
-	if resp.Diagnostics.HasError() {
-		return
+}
+
+// readS3BucketTags returns the tags of a bucket, calling the bucket's own region so that
+// buckets outside the client's region can be read. Untagged buckets return an empty map.
+func readS3BucketTags(ctx context.Context, svc *s3.Client, bucket, region string) (map[string]string, error) {
+	out, err := svc.GetBucketTagging(ctx, &s3.GetBucketTaggingInput{Bucket: aws.String(bucket)}, func(o *s3.Options) {
+		if region != "" {
+			o.Region = region
+		}
+	})
+	if err != nil {
+		if isS3ErrorCode(err, "NoSuchTagSet", "NoSuchBucket") {
+			return map[string]string{}, nil
+		}
+		return nil, err
 	}
+	tags := s3TagsToMap(out.TagSet)
+	if tags == nil {
+		tags = map[string]string{}
+	}
+	return tags, nil
+}
 
+// hasS3Tags reports whether tags contains every key of want with the same value.
+func hasS3Tags(tags, want map[string]string) bool {
+	for key, wantValue := range want {
+		if value, ok := tags[key]; !ok || value != wantValue {
+			return false
+		}
+	}
+	return true
 }
 
 // Configure adds the provider configured client to the data source.
@@ -106,12 +280,10 @@ func (d *xsynchcoAWSDataSource) Configure(_ context.Context, req datasource.Conf
 	}
 
 	client, ok := req.ProviderData.(*ClientS3)
-	// client, ok := req.ProviderData.(*session.Session)
-
 	if !ok {
 		resp.Diagnostics.AddError(
 			"Unexpected Data Source Configure Type",
-			fmt.Sprintf("Expected *session.Session, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the provider developers.", req.ProviderData),
 		)
 
 		return
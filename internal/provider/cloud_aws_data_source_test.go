@@ -0,0 +1,30 @@
+package provider
+
+import "testing"
+
+func TestS3BucketARN(t *testing.T) {
+	cases := map[string]string{
+		"us-east-1":     "arn:aws:s3:::data",
+		"cn-north-1":    "arn:aws-cn:s3:::data",
+		"us-gov-west-1": "arn:aws-us-gov:s3:::data",
+		"":              "arn:aws:s3:::data",
+	}
+	for region, want := range cases {
+		if got := s3BucketARN("data", region); got != want {
+			t.Errorf("s3BucketARN(%q) = %q, want %q", region, got, want)
+		}
+	}
+}
+
+func TestHasS3Tags(t *testing.T) {
+	tags := map[string]string{"team": "data", "env": "prod"}
+	if !hasS3Tags(tags, map[string]string{"team": "data"}) {
+		t.Error("subset of tags not matched")
+	}
+	if hasS3Tags(tags, map[string]string{"team": "web"}) {
+		t.Error("different tag value matched")
+	}
+	if hasS3Tags(tags, map[string]string{"owner": "data"}) {
+		t.Error("missing tag matched")
+	}
+}
@@ -0,0 +1,751 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &kmsKeyResource{}
+	_ resource.ResourceWithConfigure   = &kmsKeyResource{}
+	_ resource.ResourceWithModifyPlan  = &kmsKeyResource{}
+	_ resource.ResourceWithImportState = &kmsKeyResource{}
+)
+
+// kmsKeyUseActions are the permissions needed to read and write SSE-KMS encrypted objects.
+var kmsKeyUseActions = []string{
+	"kms:Decrypt",
+	"kms:DescribeKey",
+	"kms:Encrypt",
+	"kms:GenerateDataKey*",
+	"kms:ReEncrypt*",
+}
+
+type kmsKeyResourceModel struct {
+	ID                   types.String `tfsdk:"id"`
+	Last_Updated         types.String `tfsdk:"last_updated"`
+	Description          types.String `tfsdk:"description"`
+	Alias                types.String `tfsdk:"alias"`
+	KeyUsers             types.Set    `tfsdk:"key_users"`
+	Policy               types.String `tfsdk:"policy"`
+	EnableKeyRotation    types.Bool   `tfsdk:"enable_key_rotation"`
+	RotationPeriodInDays types.Int64  `tfsdk:"rotation_period_in_days"`
+	DeletionWindowInDays types.Int64  `tfsdk:"deletion_window_in_days"`
+	MultiRegion          types.Bool   `tfsdk:"multi_region"`
+	ReplicaRegions       types.Set    `tfsdk:"replica_regions"`
+	ReplicaARNs          types.Map    `tfsdk:"replica_arns"`
+	Tags                 types.Map    `tfsdk:"tags"`
+	ARN                  types.String `tfsdk:"arn"`
+}
+
+// NewKMSKeyResource is a helper function to simplify the provider implementation.
+func NewKMSKeyResource() resource.Resource {
+	return &kmsKeyResource{}
+}
+
+// kmsKeyResource manages a symmetric KMS key for S3 server-side encryption.
+type kmsKeyResource struct {
+	client *ClientS3
+}
+
+func (r *kmsKeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the developer", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *kmsKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_kms_key"
+}
+
+// Schema defines the schema for the resource.
+func (r *kmsKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	computed := []planmodifier.String{
+		stringplanmodifier.UseStateForUnknown(),
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Manages a symmetric KMS key for S3 bucket encryption, with its alias, rotation and replicas.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				Description:   "Key ID.",
+				PlanModifiers: computed,
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"description": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(""),
+			},
+			"alias": schema.StringAttribute{
+				Optional:    true,
+				Description: "Alias of the key, starting with alias/.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^alias/[a-zA-Z0-9/_-]+$`), "must be alias/ followed by letters, digits, /, _ or -"),
+					stringvalidator.RegexMatches(regexp.MustCompile(`^alias/(?:[^a]|a[^w]|aw[^s]|aws[^/])`), "must not start with alias/aws/, which is reserved"),
+				},
+			},
+			"key_users": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "ARNs of principals allowed to use the key directly. Principals of the account can always use it through S3.",
+			},
+			"policy": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Description: "JSON key policy. When unset, a policy is generated that gives the account full control, " +
+					"lets principals of the account use the key through S3, and lets key_users use it directly.",
+			},
+			"enable_key_rotation": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(true),
+			},
+			"rotation_period_in_days": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Days between automatic rotations, between 90 and 2560. KMS defaults to 365.",
+				Validators: []validator.Int64{
+					int64validator.Between(90, 2560),
+				},
+			},
+			"deletion_window_in_days": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(30),
+				Description: "Days KMS waits before deleting the key and its replicas after destroy, between 7 and 30.",
+				Validators: []validator.Int64{
+					int64validator.Between(7, 30),
+				},
+			},
+			"multi_region": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Create a multi-Region primary key that can be replicated with replica_regions.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"replica_regions": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Regions to replicate a multi-Region key to, for buckets replicated across regions.",
+			},
+			"replica_arns": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "ARNs of the replica keys, keyed by region.",
+			},
+			"tags": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"arn": schema.StringAttribute{
+				Computed:      true,
+				Description:   "Key ARN, usable as kms_master_key_id of bucket encryption.",
+				PlanModifiers: computed,
+			},
+		},
+	}
+}
+
+// ModifyPlan validates the rotation and replica settings and generates the default key
+// policy when none is configured.
+func (r *kmsKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan, config kmsKeyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.RotationPeriodInDays.IsNull() && !plan.EnableKeyRotation.IsUnknown() && !plan.EnableKeyRotation.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("rotation_period_in_days"), "Rotation Disabled",
+			"rotation_period_in_days requires enable_key_rotation to be true.")
+	}
+	if !plan.ReplicaRegions.IsNull() && len(plan.ReplicaRegions.Elements()) > 0 && !plan.MultiRegion.IsUnknown() && !plan.MultiRegion.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("replica_regions"), "Key Is Not Multi-Region",
+			"replica_regions requires multi_region to be true.")
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state *kmsKeyResourceModel
+	if !req.State.Raw.IsNull() {
+		state = &kmsKeyResourceModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if config.Policy.IsNull() {
+		if plan.KeyUsers.IsUnknown() || r.client == nil {
+			plan.Policy = types.StringUnknown()
+		} else {
+			var keyUsers []string
+			resp.Diagnostics.Append(plan.KeyUsers.ElementsAs(ctx, &keyUsers, false)...)
+			accountID, err := r.client.accountID(ctx)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error resolving account",
+					"Could not determine the AWS account of the provider credentials: "+err.Error(),
+				)
+				return
+			}
+			document, err := defaultKMSKeyPolicy(awsPartition(r.client.KMSClient.Options().Region), accountID, keyUsers)
+			if err != nil {
+				resp.Diagnostics.AddError("Error generating key policy", err.Error())
+				return
+			}
+			plan.Policy = types.StringValue(document)
+		}
+	}
+	if config.RotationPeriodInDays.IsNull() && state != nil && plan.EnableKeyRotation.Equal(state.EnableKeyRotation) {
+		plan.RotationPeriodInDays = state.RotationPeriodInDays
+	}
+	if state != nil && plan.ReplicaRegions.Equal(state.ReplicaRegions) {
+		plan.ReplicaARNs = state.ReplicaARNs
+	}
+	if state != nil && !plan.Policy.Equal(state.Policy) {
+		plan.Last_Updated = types.StringUnknown()
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *kmsKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan kmsKeyResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tags, diags := kmsTagsFromMap(ctx, plan.Tags)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	svc := r.client.KMSClient
+	out, err := svc.CreateKey(ctx, &kms.CreateKeyInput{
+		Description: aws.String(plan.Description.ValueString()),
+		KeySpec:     kmstypes.KeySpecSymmetricDefault,
+		KeyUsage:    kmstypes.KeyUsageTypeEncryptDecrypt,
+		MultiRegion: aws.Bool(plan.MultiRegion.ValueBool()),
+		Policy:      aws.String(plan.Policy.ValueString()),
+		Tags:        tags,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Error creating KMS key", "Could not create KMS key: "+err.Error())
+		return
+	}
+	plan.ID = types.StringPointerValue(out.KeyMetadata.KeyId)
+	plan.ARN = types.StringPointerValue(out.KeyMetadata.Arn)
+
+	// The key exists from here on; settings that fail to apply are left for the next apply.
+	if err := r.putRotation(ctx, plan); err != nil {
+		resp.Diagnostics.AddError("Error configuring key rotation", "Could not configure rotation of key "+plan.ID.ValueString()+": "+err.Error())
+		plan.EnableKeyRotation = types.BoolValue(false)
+	}
+	if !plan.Alias.IsNull() {
+		_, err := svc.CreateAlias(ctx, &kms.CreateAliasInput{AliasName: plan.Alias.ValueStringPointer(), TargetKeyId: plan.ID.ValueStringPointer()})
+		if err != nil {
+			resp.Diagnostics.AddError("Error creating key alias", "Could not create alias "+plan.Alias.ValueString()+": "+err.Error())
+			plan.Alias = types.StringNull()
+		}
+	}
+
+	replicas := map[string]string{}
+	regions, diags := kmsReplicaRegions(ctx, plan.ReplicaRegions)
+	resp.Diagnostics.Append(diags...)
+	for _, region := range regions {
+		arn, err := r.replicate(ctx, plan, region, tags)
+		if err != nil {
+			resp.Diagnostics.AddError("Error replicating KMS key", "Could not replicate key "+plan.ID.ValueString()+" to "+region+": "+err.Error())
+			continue
+		}
+		replicas[region] = arn
+	}
+	replicaARNs, diags := types.MapValueFrom(ctx, types.StringType, replicas)
+	resp.Diagnostics.Append(diags...)
+	plan.ReplicaARNs = replicaARNs
+
+	resp.Diagnostics.Append(r.refreshRotation(ctx, &plan)...)
+	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *kmsKeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state kmsKeyResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	svc := r.client.KMSClient
+	keyID := state.ID.ValueStringPointer()
+	out, err := svc.DescribeKey(ctx, &kms.DescribeKeyInput{KeyId: keyID})
+	if isS3ErrorCode(err, "NotFoundException") {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading KMS key", "Could not describe key "+state.ID.ValueString()+": "+err.Error())
+		return
+	}
+	metadata := out.KeyMetadata
+	if metadata.KeyState == kmstypes.KeyStatePendingDeletion || metadata.KeyState == kmstypes.KeyStatePendingReplicaDeletion {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+	state.ARN = types.StringPointerValue(metadata.Arn)
+	state.Description = types.StringValue(aws.ToString(metadata.Description))
+	state.MultiRegion = types.BoolValue(aws.ToBool(metadata.MultiRegion))
+
+	replicas := map[string]string{}
+	if config := metadata.MultiRegionConfiguration; config != nil {
+		for _, replica := range config.ReplicaKeys {
+			replicas[aws.ToString(replica.Region)] = aws.ToString(replica.Arn)
+		}
+	}
+	if len(replicas) > 0 || !state.ReplicaRegions.IsNull() {
+		regions := make([]string, 0, len(replicas))
+		for region := range replicas {
+			regions = append(regions, region)
+		}
+		replicaRegions, diags := types.SetValueFrom(ctx, types.StringType, regions)
+		resp.Diagnostics.Append(diags...)
+		state.ReplicaRegions = replicaRegions
+	}
+	replicaARNs, diags := types.MapValueFrom(ctx, types.StringType, replicas)
+	resp.Diagnostics.Append(diags...)
+	state.ReplicaARNs = replicaARNs
+
+	policy, err := svc.GetKeyPolicy(ctx, &kms.GetKeyPolicyInput{KeyId: keyID, PolicyName: aws.String("default")})
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading key policy", "Could not read the policy of key "+state.ID.ValueString()+": "+err.Error())
+		return
+	}
+	if !equivalentPolicyJSON(state.Policy.ValueString(), aws.ToString(policy.Policy)) {
+		state.Policy = types.StringPointerValue(policy.Policy)
+	}
+
+	resp.Diagnostics.Append(r.refreshRotation(ctx, &state)...)
+
+	aliases := kms.NewListAliasesPaginator(svc, &kms.ListAliasesInput{KeyId: keyID})
+	var aliasNames []string
+	for aliases.HasMorePages() {
+		page, err := aliases.NextPage(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError("Error reading key aliases", "Could not list the aliases of key "+state.ID.ValueString()+": "+err.Error())
+			return
+		}
+		for _, alias := range page.Aliases {
+			aliasNames = append(aliasNames, aws.ToString(alias.AliasName))
+		}
+	}
+	state.Alias = kmsKeyAlias(state.Alias, aliasNames)
+
+	tags, err := r.readTags(ctx, state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Error reading key tags", "Could not read the tags of key "+state.ID.ValueString()+": "+err.Error())
+		return
+	}
+	if len(tags) > 0 || !state.Tags.IsNull() {
+		tagMap, diags := types.MapValueFrom(ctx, types.StringType, tags)
+		resp.Diagnostics.Append(diags...)
+		state.Tags = tagMap
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *kmsKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state kmsKeyResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	svc := r.client.KMSClient
+	keyID := plan.ID.ValueStringPointer()
+	fail := func(action string, err error) {
+		resp.Diagnostics.AddError("Error updating KMS key", "Could not "+action+" of key "+plan.ID.ValueString()+": "+err.Error())
+	}
+
+	if !plan.Description.Equal(state.Description) {
+		if _, err := svc.UpdateKeyDescription(ctx, &kms.UpdateKeyDescriptionInput{KeyId: keyID, Description: plan.Description.ValueStringPointer()}); err != nil {
+			fail("update the description", err)
+			return
+		}
+	}
+
+	replicas := map[string]string{}
+	resp.Diagnostics.Append(state.ReplicaARNs.ElementsAs(ctx, &replicas, false)...)
+	if !plan.Policy.Equal(state.Policy) {
+		if _, err := svc.PutKeyPolicy(ctx, &kms.PutKeyPolicyInput{KeyId: keyID, PolicyName: aws.String("default"), Policy: plan.Policy.ValueStringPointer()}); err != nil {
+			fail("update the policy", err)
+			return
+		}
+		// Replicas have policies of their own; keep them in line with the primary.
+		for region, arn := range replicas {
+			_, err := svc.PutKeyPolicy(ctx, &kms.PutKeyPolicyInput{KeyId: aws.String(arn), PolicyName: aws.String("default"), Policy: plan.Policy.ValueStringPointer()}, inKMSRegion(region))
+			if err != nil {
+				fail("update the policy of the replica in "+region, err)
+				return
+			}
+		}
+	}
+
+	if !plan.EnableKeyRotation.Equal(state.EnableKeyRotation) || !plan.RotationPeriodInDays.Equal(state.RotationPeriodInDays) {
+		if err := r.putRotation(ctx, plan); err != nil {
+			fail("configure rotation", err)
+			return
+		}
+	}
+
+	if !plan.Alias.Equal(state.Alias) {
+		if !plan.Alias.IsNull() {
+			if _, err := svc.CreateAlias(ctx, &kms.CreateAliasInput{AliasName: plan.Alias.ValueStringPointer(), TargetKeyId: keyID}); err != nil {
+				fail("create alias "+plan.Alias.ValueString(), err)
+				return
+			}
+		}
+		if !state.Alias.IsNull() {
+			if _, err := svc.DeleteAlias(ctx, &kms.DeleteAliasInput{AliasName: state.Alias.ValueStringPointer()}); err != nil && !isS3ErrorCode(err, "NotFoundException") {
+				fail("delete alias "+state.Alias.ValueString(), err)
+				return
+			}
+		}
+	}
+
+	if !plan.Tags.Equal(state.Tags) {
+		if err := r.updateTags(ctx, plan, state); err != nil {
+			fail("update the tags", err)
+			return
+		}
+	}
+
+	regions, diags := kmsReplicaRegions(ctx, plan.ReplicaRegions)
+	resp.Diagnostics.Append(diags...)
+	tags, diags := kmsTagsFromMap(ctx, plan.Tags)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	wanted := map[string]bool{}
+	for _, region := range regions {
+		wanted[region] = true
+		if _, ok := replicas[region]; ok {
+			continue
+		}
+		arn, err := r.replicate(ctx, plan, region, tags)
+		if err != nil {
+			fail("replicate to "+region, err)
+			break
+		}
+		replicas[region] = arn
+	}
+	for region, arn := range replicas {
+		if wanted[region] {
+			continue
+		}
+		if err := scheduleKMSKeyDeletion(ctx, svc, arn, plan.DeletionWindowInDays.ValueInt64(), inKMSRegion(region)); err != nil {
+			fail("delete the replica in "+region, err)
+			break
+		}
+		delete(replicas, region)
+	}
+	replicaARNs, diags := types.MapValueFrom(ctx, types.StringType, replicas)
+	resp.Diagnostics.Append(diags...)
+	plan.ReplicaARNs = replicaARNs
+
+	resp.Diagnostics.Append(r.refreshRotation(ctx, &plan)...)
+	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete schedules the key and its replicas for deletion after the deletion window. KMS does
+// not delete keys immediately.
+func (r *kmsKeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state kmsKeyResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	svc := r.client.KMSClient
+	window := state.DeletionWindowInDays.ValueInt64()
+	if !state.Alias.IsNull() {
+		_, err := svc.DeleteAlias(ctx, &kms.DeleteAliasInput{AliasName: state.Alias.ValueStringPointer()})
+		if err != nil && !isS3ErrorCode(err, "NotFoundException") {
+			resp.Diagnostics.AddError("Error deleting key alias", "Could not delete alias "+state.Alias.ValueString()+": "+err.Error())
+			return
+		}
+	}
+
+	// A primary key with replicas waits in PendingReplicaDeletion until the replicas are gone.
+	replicas := map[string]string{}
+	resp.Diagnostics.Append(state.ReplicaARNs.ElementsAs(ctx, &replicas, false)...)
+	for region, arn := range replicas {
+		if err := scheduleKMSKeyDeletion(ctx, svc, arn, window, inKMSRegion(region)); err != nil {
+			resp.Diagnostics.AddError("Error deleting KMS key replica", "Could not schedule deletion of the replica in "+region+": "+err.Error())
+			return
+		}
+	}
+	if err := scheduleKMSKeyDeletion(ctx, svc, state.ID.ValueString(), window); err != nil {
+		resp.Diagnostics.AddError("Error deleting KMS key", "Could not schedule deletion of key "+state.ID.ValueString()+": "+err.Error())
+	}
+}
+
+// ImportState imports an existing key by key ID or key ARN. Read adopts the key's alias
+// when it has exactly one.
+func (r *kmsKeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	out, err := r.client.KMSClient.DescribeKey(ctx, &kms.DescribeKeyInput{KeyId: aws.String(req.ID)})
+	if err != nil {
+		resp.Diagnostics.AddError("Error importing KMS key", "Could not describe key "+req.ID+": "+err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), aws.ToString(out.KeyMetadata.KeyId))...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_window_in_days"), 30)...)
+}
+
+func (r *kmsKeyResource) putRotation(ctx context.Context, model kmsKeyResourceModel) error {
+	if !model.EnableKeyRotation.ValueBool() {
+		_, err := r.client.KMSClient.DisableKeyRotation(ctx, &kms.DisableKeyRotationInput{KeyId: model.ID.ValueStringPointer()})
+		return err
+	}
+	input := &kms.EnableKeyRotationInput{KeyId: model.ID.ValueStringPointer()}
+	if !model.RotationPeriodInDays.IsNull() && !model.RotationPeriodInDays.IsUnknown() {
+		input.RotationPeriodInDays = aws.Int32(int32(model.RotationPeriodInDays.ValueInt64()))
+	}
+	_, err := r.client.KMSClient.EnableKeyRotation(ctx, input)
+	return err
+}
+
+// refreshRotation reads the rotation settings into model.
+func (r *kmsKeyResource) refreshRotation(ctx context.Context, model *kmsKeyResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	out, err := r.client.KMSClient.GetKeyRotationStatus(ctx, &kms.GetKeyRotationStatusInput{KeyId: model.ID.ValueStringPointer()})
+	if err != nil {
+		diags.AddError("Error reading key rotation", "Could not read the rotation status of key "+model.ID.ValueString()+": "+err.Error())
+		if model.RotationPeriodInDays.IsUnknown() {
+			model.RotationPeriodInDays = types.Int64Null()
+		}
+		return diags
+	}
+	model.EnableKeyRotation = types.BoolValue(out.KeyRotationEnabled)
+	model.RotationPeriodInDays = types.Int64Null()
+	if out.RotationPeriodInDays != nil {
+		model.RotationPeriodInDays = types.Int64Value(int64(*out.RotationPeriodInDays))
+	}
+	return diags
+}
+
+// replicate creates a replica of the primary key in region and returns its ARN.
+func (r *kmsKeyResource) replicate(ctx context.Context, model kmsKeyResourceModel, region string, tags []kmstypes.Tag) (string, error) {
+	out, err := r.client.KMSClient.ReplicateKey(ctx, &kms.ReplicateKeyInput{
+		KeyId:         model.ID.ValueStringPointer(),
+		ReplicaRegion: aws.String(region),
+		Description:   model.Description.ValueStringPointer(),
+		Policy:        model.Policy.ValueStringPointer(),
+		Tags:          tags,
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(out.ReplicaKeyMetadata.Arn), nil
+}
+
+func (r *kmsKeyResource) readTags(ctx context.Context, keyID string) (map[string]string, error) {
+	tags := map[string]string{}
+	paginator := kms.NewListResourceTagsPaginator(r.client.KMSClient, &kms.ListResourceTagsInput{KeyId: aws.String(keyID)})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range page.Tags {
+			tags[aws.ToString(tag.TagKey)] = aws.ToString(tag.TagValue)
+		}
+	}
+	return tags, nil
+}
+
+// updateTags applies the planned tags, removing keys that are no longer configured.
+func (r *kmsKeyResource) updateTags(ctx context.Context, plan, state kmsKeyResourceModel) error {
+	keyID := plan.ID.ValueStringPointer()
+	return updateAWSTags(ctx, plan.Tags, state.Tags,
+		func(keys []string) error {
+			_, err := r.client.KMSClient.UntagResource(ctx, &kms.UntagResourceInput{KeyId: keyID, TagKeys: keys})
+			return err
+		},
+		func(tags map[string]string) error {
+			_, err := r.client.KMSClient.TagResource(ctx, &kms.TagResourceInput{KeyId: keyID, Tags: kmsTags(tags)})
+			return err
+		},
+	)
+}
+
+func scheduleKMSKeyDeletion(ctx context.Context, svc *kms.Client, keyID string, window int64, optFns ...func(*kms.Options)) error {
+	_, err := svc.ScheduleKeyDeletion(ctx, &kms.ScheduleKeyDeletionInput{
+		KeyId:               aws.String(keyID),
+		PendingWindowInDays: aws.Int32(int32(window)),
+	}, optFns...)
+	if isS3ErrorCode(err, "NotFoundException", "KMSInvalidStateException") {
+		// Already gone or already pending deletion.
+		return nil
+	}
+	return err
+}
+
+// inKMSRegion sends a request to the KMS endpoint of another region, where replicas live.
+func inKMSRegion(region string) func(*kms.Options) {
+	return func(o *kms.Options) {
+		o.Region = region
+	}
+}
+
+// defaultKMSKeyPolicy is the policy of keys created without one. The account keeps full
+// control so IAM policies apply, principals of the account may use the key only through
+// S3, and keyUsers may use it directly.
+func defaultKMSKeyPolicy(partition, accountID string, keyUsers []string) (string, error) {
+	type statement struct {
+		Sid       string                    `json:"Sid"`
+		Effect    string                    `json:"Effect"`
+		Principal map[string]any            `json:"Principal"`
+		Action    any                       `json:"Action"`
+		Resource  string                    `json:"Resource"`
+		Condition map[string]map[string]any `json:"Condition,omitempty"`
+	}
+	statements := []statement{
+		{
+			Sid:       "AccountAdministration",
+			Effect:    "Allow",
+			Principal: map[string]any{"AWS": "arn:" + partition + ":iam::" + accountID + ":root"},
+			Action:    "kms:*",
+			Resource:  "*",
+		},
+		{
+			Sid:       "UseThroughS3",
+			Effect:    "Allow",
+			Principal: map[string]any{"AWS": "*"},
+			Action:    kmsKeyUseActions,
+			Resource:  "*",
+			Condition: map[string]map[string]any{
+				"StringEquals": {"kms:CallerAccount": accountID},
+				"StringLike":   {"kms:ViaService": "s3.*." + awsPartitionDNSSuffix(partition)},
+			},
+		},
+	}
+	if len(keyUsers) > 0 {
+		users := append([]string(nil), keyUsers...)
+		sort.Strings(users)
+		statements = append(statements, statement{
+			Sid:       "KeyUsers",
+			Effect:    "Allow",
+			Principal: map[string]any{"AWS": users},
+			Action:    kmsKeyUseActions,
+			Resource:  "*",
+		})
+	}
+
+	document, err := json.Marshal(map[string]any{"Version": "2012-10-17", "Statement": statements})
+	if err != nil {
+		return "", err
+	}
+	return string(document), nil
+}
+
+func kmsReplicaRegions(ctx context.Context, set types.Set) ([]string, diag.Diagnostics) {
+	var regions []string
+	if set.IsNull() || set.IsUnknown() {
+		return regions, nil
+	}
+	diags := set.ElementsAs(ctx, &regions, false)
+	return regions, diags
+}
+
+// kmsKeyAlias picks the alias to keep in state from the aliases the key has. The known
+// alias wins; otherwise a single alias is adopted, so an imported key whose alias is in the
+// configuration does not try to create it again.
+func kmsKeyAlias(known types.String, aliases []string) types.String {
+	switch {
+	case !known.IsNull() && slices.Contains(aliases, known.ValueString()):
+		return known
+	case len(aliases) == 1:
+		return types.StringValue(aliases[0])
+	default:
+		return types.StringNull()
+	}
+}
+
+func kmsTagsFromMap(ctx context.Context, tags types.Map) ([]kmstypes.Tag, diag.Diagnostics) {
+	values := map[string]string{}
+	diags := tags.ElementsAs(ctx, &values, false)
+	return kmsTags(values), diags
+}
+
+func kmsTags(tags map[string]string) []kmstypes.Tag {
+	var tagSet []kmstypes.Tag
+	for key, value := range tags {
+		tagSet = append(tagSet, kmstypes.Tag{TagKey: aws.String(key), TagValue: aws.String(value)})
+	}
+	return tagSet
+}
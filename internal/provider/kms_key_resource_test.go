@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestDefaultKMSKeyPolicy(t *testing.T) {
+	document, err := defaultKMSKeyPolicy("aws-cn", "111122223333", []string{
+		"arn:aws-cn:iam::111122223333:role/writer",
+		"arn:aws-cn:iam::111122223333:role/reader",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var policy struct {
+		Statement []struct {
+			Sid       string
+			Principal map[string]any
+			Condition map[string]map[string]any
+		}
+	}
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		t.Fatal(err)
+	}
+	if len(policy.Statement) != 3 {
+		t.Fatalf("got %d statements, want 3: %s", len(policy.Statement), document)
+	}
+	if root := policy.Statement[0].Principal["AWS"]; root != "arn:aws-cn:iam::111122223333:root" {
+		t.Errorf("administration principal = %v", root)
+	}
+	if via := policy.Statement[1].Condition["StringLike"]["kms:ViaService"]; via != "s3.*.amazonaws.com.cn" {
+		t.Errorf("S3 statement condition = %v", policy.Statement[1].Condition)
+	}
+	users, _ := policy.Statement[2].Principal["AWS"].([]any)
+	if len(users) != 2 || users[0] != "arn:aws-cn:iam::111122223333:role/reader" {
+		t.Errorf("key users = %v, want both roles sorted", users)
+	}
+
+	document, err = defaultKMSKeyPolicy("aws", "111122223333", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(document), &policy); err != nil || len(policy.Statement) != 2 {
+		t.Errorf("policy without key users = %s", document)
+	}
+}
+
+func TestKMSKeyAlias(t *testing.T) {
+	cases := []struct {
+		name    string
+		known   types.String
+		aliases []string
+		want    types.String
+	}{
+		{"imported with one alias", types.StringNull(), []string{"alias/data"}, types.StringValue("alias/data")},
+		{"known alias kept", types.StringValue("alias/b"), []string{"alias/a", "alias/b"}, types.StringValue("alias/b")},
+		{"renamed outside Terraform", types.StringValue("alias/old"), []string{"alias/new"}, types.StringValue("alias/new")},
+		{"deleted outside Terraform", types.StringValue("alias/old"), nil, types.StringNull()},
+		{"ambiguous", types.StringNull(), []string{"alias/a", "alias/b"}, types.StringNull()},
+	}
+	for _, tc := range cases {
+		if got := kmsKeyAlias(tc.known, tc.aliases); !got.Equal(tc.want) {
+			t.Errorf("%s: alias = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
@@ -7,8 +7,14 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3control"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
@@ -26,7 +32,18 @@ var (
 
 type ClientS3 struct {
 	S3Client *s3.Client
-	Region   string
+	// S3AccelerateClient sends requests through the Transfer Acceleration endpoint.
+	S3AccelerateClient *s3.Client
+	// S3ControlClient manages account-level S3 resources such as access points.
+	S3ControlClient *s3control.Client
+	STSClient       *sts.Client
+	// IAMClient manages the roles and users that are granted access to buckets.
+	IAMClient *iam.Client
+	// KMSClient manages the keys used for bucket encryption.
+	KMSClient *kms.Client
+	Region    string
+
+	account awsAccount
 }
 
 type xsynchco struct {
@@ -50,9 +67,23 @@ func NewClientS3(region string) (*ClientS3, error) {
 		return &ClientS3{}, errors.New("error loading aws configuration information")
 
 	}
+	// Requests to directory buckets are signed with short-lived CreateSession credentials
+	// from the SDK's S3 Express credentials provider, which caches them per client. Sharing
+	// one S3Client keeps a session per bucket instead of one per request.
 	s3Client := s3.NewFromConfig(sdkConfig)
-
-	return &ClientS3{S3Client: s3Client, Region: region}, nil
+	s3AccelerateClient := s3.NewFromConfig(sdkConfig, func(o *s3.Options) {
+		o.UseAccelerate = true
+	})
+
+	return &ClientS3{
+		S3Client:           s3Client,
+		S3AccelerateClient: s3AccelerateClient,
+		S3ControlClient:    s3control.NewFromConfig(sdkConfig),
+		STSClient:          sts.NewFromConfig(sdkConfig),
+		IAMClient:          iam.NewFromConfig(sdkConfig),
+		KMSClient:          kms.NewFromConfig(sdkConfig),
+		Region:             region,
+	}, nil
 }
 
 func newAZClient(region string) (*azureProviderStruct, error){
@@ -68,7 +99,9 @@ func newAZClient(region string) (*azureProviderStruct, error){
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ provider.Provider = &xsynchProvider{}
+	_ provider.Provider                       = &xsynchProvider{}
+	_ provider.ProviderWithEphemeralResources = &xsynchProvider{}
+	_ provider.ProviderWithFunctions          = &xsynchProvider{}
 )
 
 // New is a helper function to simplify provider server and testing implementation.
@@ -163,6 +196,7 @@ func (p *xsynchProvider) Configure(ctx context.Context, req provider.ConfigureRe
 		}
 		resp.DataSourceData = awsClient
 		resp.ResourceData = awsClient
+		resp.EphemeralResourceData = awsClient
 	
 		tflog.Info(ctx, "Configured AWS Client", map[string]any{"success": true})
 	case "azure":
@@ -199,6 +233,14 @@ func (p *xsynchProvider) Configure(ctx context.Context, req provider.ConfigureRe
 func (p *xsynchProvider) DataSources(_ context.Context) []func() datasource.DataSource {
 	return []func() datasource.DataSource{
 		NewXsynchcoAWSDataSource,
+		NewS3ObjectsDataSource,
+		NewS3ObjectDataSource,
+		NewS3BucketDataSource,
+		NewAWSCallerIdentityDataSource,
+		NewAzureClientConfigDataSource,
+		NewS3BucketUsageDataSource,
+		NewS3SecurityReportDataSource,
+		NewS3AccessPolicyDataSource,
 	}
 }
 
@@ -207,6 +249,32 @@ func (p *xsynchProvider) Resources(_ context.Context) []func() resource.Resource
 	return []func() resource.Resource{
 		NewS3Resource,
 		NewAzureStorageResource,
+		NewS3NotificationResource,
+		NewS3IntelligentTieringResource,
+		NewS3ObjectResource,
+		NewS3DirectorySyncResource,
+		NewS3InventoryResource,
+		NewS3AccessPointResource,
+		NewS3AnalyticsResource,
+		NewS3MetricsResource,
+		NewS3BucketAccessResource,
+		NewKMSKeyResource,
+		NewAzureStorageAccountResource,
+	}
+}
+
+// EphemeralResources defines the ephemeral resources implemented in the provider.
+func (p *xsynchProvider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewS3PresignedURLEphemeralResource,
+	}
+}
+
+// Functions defines the functions implemented in the provider.
+func (p *xsynchProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewS3PresignedURLFunction,
+		NewS3AccessPolicyFunction,
 	}
 }
 
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// s3AccelerateEndpoint is the host name that routes requests for bucket through the
+// nearest CloudFront edge location.
+func s3AccelerateEndpoint(bucket string) string {
+	return bucket + ".s3-accelerate.amazonaws.com"
+}
+
+// uploadClient returns the client used for provider-managed uploads, going through the
+// Transfer Acceleration endpoint when accelerate is set. The bucket must have acceleration
+// enabled or S3 rejects the request.
+func (c *ClientS3) uploadClient(accelerate bool) *s3.Client {
+	if accelerate && c.S3AccelerateClient != nil {
+		return c.S3AccelerateClient
+	}
+	return c.S3Client
+}
+
+// putS3BucketAcceleration enables or suspends Transfer Acceleration on a bucket.
+func putS3BucketAcceleration(ctx context.Context, svc *s3.Client, bucket string, enabled bool) error {
+	status := awstypes.BucketAccelerateStatusSuspended
+	if enabled {
+		status = awstypes.BucketAccelerateStatusEnabled
+	}
+	_, err := svc.PutBucketAccelerateConfiguration(ctx, &s3.PutBucketAccelerateConfigurationInput{
+		Bucket:                  aws.String(bucket),
+		AccelerateConfiguration: &awstypes.AccelerateConfiguration{Status: status},
+	})
+	return err
+}
+
+// readS3BucketAcceleration reports whether Transfer Acceleration is enabled. Buckets that
+// never had it configured, and regions that do not offer it, read as not enabled.
+func readS3BucketAcceleration(ctx context.Context, svc *s3.Client, bucket string) (bool, error) {
+	out, err := svc.GetBucketAccelerateConfiguration(ctx, &s3.GetBucketAccelerateConfigurationInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if isS3ErrorCode(err, "UnsupportedArgument", "MethodNotAllowed", "NotImplemented") {
+			return false, nil
+		}
+		return false, err
+	}
+	return out.Status == awstypes.BucketAccelerateStatusEnabled, nil
+}
+
+// putS3BucketRequestPayer sets who pays for requests and data transfer, either
+// BucketOwner or Requester.
+func putS3BucketRequestPayer(ctx context.Context, svc *s3.Client, bucket string, payer string) error {
+	_, err := svc.PutBucketRequestPayment(ctx, &s3.PutBucketRequestPaymentInput{
+		Bucket:                      aws.String(bucket),
+		RequestPaymentConfiguration: &awstypes.RequestPaymentConfiguration{Payer: awstypes.Payer(payer)},
+	})
+	return err
+}
+
+func readS3BucketRequestPayer(ctx context.Context, svc *s3.Client, bucket string) (string, error) {
+	out, err := svc.GetBucketRequestPayment(ctx, &s3.GetBucketRequestPaymentInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return "", err
+	}
+	if out.Payer == "" {
+		return string(awstypes.PayerBucketOwner), nil
+	}
+	return string(out.Payer), nil
+}
@@ -0,0 +1,413 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3control"
+	controltypes "github.com/aws/aws-sdk-go-v2/service/s3control/types"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &s3AccessPointResource{}
+	_ resource.ResourceWithConfigure   = &s3AccessPointResource{}
+	_ resource.ResourceWithImportState = &s3AccessPointResource{}
+)
+
+type s3AccessPointResourceModel struct {
+	ID                    types.String `tfsdk:"id"`
+	Last_Updated          types.String `tfsdk:"last_updated"`
+	AccountID             types.String `tfsdk:"account_id"`
+	Name                  types.String `tfsdk:"name"`
+	Bucket                types.String `tfsdk:"bucket"`
+	BucketAccountID       types.String `tfsdk:"bucket_account_id"`
+	VpcID                 types.String `tfsdk:"vpc_id"`
+	BlockPublicAcls       types.Bool   `tfsdk:"block_public_acls"`
+	BlockPublicPolicy     types.Bool   `tfsdk:"block_public_policy"`
+	IgnorePublicAcls      types.Bool   `tfsdk:"ignore_public_acls"`
+	RestrictPublicBuckets types.Bool   `tfsdk:"restrict_public_buckets"`
+	Policy                types.String `tfsdk:"policy"`
+	Alias                 types.String `tfsdk:"alias"`
+	ARN                   types.String `tfsdk:"arn"`
+	NetworkOrigin         types.String `tfsdk:"network_origin"`
+	Endpoints             types.Map    `tfsdk:"endpoints"`
+}
+
+// NewS3AccessPointResource is a helper function to simplify the provider implementation.
+func NewS3AccessPointResource() resource.Resource {
+	return &s3AccessPointResource{}
+}
+
+// s3AccessPointResource manages an S3 access point and its policy.
+type s3AccessPointResource struct {
+	client *ClientS3
+}
+
+func (r *s3AccessPointResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the developer", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *s3AccessPointResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_access_point"
+}
+
+// Schema defines the schema for the resource.
+func (r *s3AccessPointResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	// S3 cannot change an access point after creation, only its policy.
+	publicAccessBlock := func(description string) schema.BoolAttribute {
+		return schema.BoolAttribute{
+			Optional:    true,
+			Computed:    true,
+			Default:     booldefault.StaticBool(true),
+			Description: description,
+			PlanModifiers: []planmodifier.Bool{
+				boolplanmodifier.RequiresReplace(),
+			},
+		}
+	}
+	requiresReplace := []planmodifier.String{
+		stringplanmodifier.RequiresReplace(),
+	}
+	computed := []planmodifier.String{
+		stringplanmodifier.UseStateForUnknown(),
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Manages an S3 access point, a named network endpoint with its own policy for one bucket.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				Description:   "account_id:name of the access point.",
+				PlanModifiers: computed,
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"account_id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Account that owns the access point. Defaults to the account of the provider credentials.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:      true,
+				PlanModifiers: requiresReplace,
+			},
+			"bucket": schema.StringAttribute{
+				Required:      true,
+				PlanModifiers: requiresReplace,
+			},
+			"bucket_account_id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Account that owns the bucket, for access points on buckets of another account.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"vpc_id": schema.StringAttribute{
+				Optional:      true,
+				Description:   "Only accept requests from this VPC. The access point is reachable from the internet when unset.",
+				PlanModifiers: requiresReplace,
+			},
+			"block_public_acls":       publicAccessBlock("Reject requests that grant public access through ACLs."),
+			"block_public_policy":     publicAccessBlock("Reject access point policies that grant public access."),
+			"ignore_public_acls":      publicAccessBlock("Ignore public ACLs on objects accessed through the access point."),
+			"restrict_public_buckets": publicAccessBlock("Only allow AWS principals and authorized users when the policy is public."),
+			"policy": schema.StringAttribute{
+				Optional:    true,
+				Description: "JSON access point policy.",
+			},
+			"alias": schema.StringAttribute{
+				Computed:      true,
+				Description:   "Alias that can be used wherever a bucket name is accepted.",
+				PlanModifiers: computed,
+			},
+			"arn": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: computed,
+			},
+			"network_origin": schema.StringAttribute{
+				Computed:      true,
+				Description:   "Internet or VPC.",
+				PlanModifiers: computed,
+			},
+			"endpoints": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Endpoint host names of the access point, keyed by type such as ipv4 or dualstack.",
+			},
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *s3AccessPointResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan s3AccessPointResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.AccountID.IsUnknown() || plan.AccountID.IsNull() {
+		accountID, err := r.client.accountID(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error resolving account",
+				"Could not determine the AWS account of the provider credentials: "+err.Error(),
+			)
+			return
+		}
+		plan.AccountID = types.StringValue(accountID)
+	}
+
+	input := &s3control.CreateAccessPointInput{
+		AccountId:       aws.String(plan.AccountID.ValueString()),
+		Name:            aws.String(plan.Name.ValueString()),
+		Bucket:          aws.String(plan.Bucket.ValueString()),
+		BucketAccountId: optionalString(plan.BucketAccountID.ValueString()),
+		PublicAccessBlockConfiguration: &controltypes.PublicAccessBlockConfiguration{
+			BlockPublicAcls:       aws.Bool(plan.BlockPublicAcls.ValueBool()),
+			BlockPublicPolicy:     aws.Bool(plan.BlockPublicPolicy.ValueBool()),
+			IgnorePublicAcls:      aws.Bool(plan.IgnorePublicAcls.ValueBool()),
+			RestrictPublicBuckets: aws.Bool(plan.RestrictPublicBuckets.ValueBool()),
+		},
+	}
+	if !plan.VpcID.IsNull() {
+		input.VpcConfiguration = &controltypes.VpcConfiguration{VpcId: plan.VpcID.ValueStringPointer()}
+	}
+	_, err := r.client.S3ControlClient.CreateAccessPoint(ctx, input)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating access point",
+			"Could not create access point "+plan.Name.ValueString()+" for bucket "+plan.Bucket.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+	plan.ID = types.StringValue(plan.AccountID.ValueString() + ":" + plan.Name.ValueString())
+
+	if !plan.Policy.IsNull() {
+		err = r.putPolicy(ctx, plan)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error setting access point policy",
+				"Access point "+plan.Name.ValueString()+" was created but its policy could not be set: "+err.Error(),
+			)
+			// Keep the access point in state so the next apply retries the policy.
+			plan.Policy = types.StringNull()
+		}
+	}
+
+	if refreshDiags := r.refresh(ctx, &plan); refreshDiags.HasError() {
+		// The access point exists, so it is still recorded; the next Read fills these in.
+		resp.Diagnostics.Append(refreshDiags...)
+		plan.BucketAccountID = types.StringNull()
+		plan.Alias = types.StringNull()
+		plan.ARN = types.StringNull()
+		plan.NetworkOrigin = types.StringNull()
+		plan.Endpoints = types.MapNull(types.StringType)
+	}
+	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *s3AccessPointResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state s3AccessPointResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	out, err := r.client.S3ControlClient.GetAccessPointPolicy(ctx, &s3control.GetAccessPointPolicyInput{
+		AccountId: aws.String(state.AccountID.ValueString()),
+		Name:      aws.String(state.Name.ValueString()),
+	})
+	switch {
+	case isS3ErrorCode(err, "NoSuchAccessPoint"):
+		resp.State.RemoveResource(ctx)
+		return
+	case isS3ErrorCode(err, "NoSuchAccessPointPolicy"):
+		state.Policy = types.StringNull()
+	case err != nil:
+		resp.Diagnostics.AddError(
+			"Error reading access point policy",
+			"Could not read the policy of access point "+state.Name.ValueString()+": "+err.Error(),
+		)
+		return
+	case !equivalentPolicyJSON(state.Policy.ValueString(), aws.ToString(out.Policy)):
+		state.Policy = types.StringPointerValue(out.Policy)
+	}
+
+	resp.Diagnostics.Append(r.refresh(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success. Only the
+// policy can change in place.
+func (r *s3AccessPointResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state s3AccessPointResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var err error
+	switch {
+	case !plan.Policy.IsNull():
+		err = r.putPolicy(ctx, plan)
+	case !state.Policy.IsNull():
+		_, err = r.client.S3ControlClient.DeleteAccessPointPolicy(ctx, &s3control.DeleteAccessPointPolicyInput{
+			AccountId: aws.String(plan.AccountID.ValueString()),
+			Name:      aws.String(plan.Name.ValueString()),
+		})
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error updating access point policy",
+			"Could not update the policy of access point "+plan.Name.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(r.refresh(ctx, &plan)...)
+	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags := resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *s3AccessPointResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state s3AccessPointResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := r.client.S3ControlClient.DeleteAccessPoint(ctx, &s3control.DeleteAccessPointInput{
+		AccountId: aws.String(state.AccountID.ValueString()),
+		Name:      aws.String(state.Name.ValueString()),
+	})
+	if err != nil && !isS3ErrorCode(err, "NoSuchAccessPoint") {
+		resp.Diagnostics.AddError(
+			"Error deleting access point",
+			"Could not delete access point "+state.Name.ValueString()+": "+err.Error(),
+		)
+	}
+}
+
+// ImportState imports an existing access point by name, or by account_id:name for access
+// points of another account.
+func (r *s3AccessPointResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	accountID, name, found := strings.Cut(req.ID, ":")
+	if !found {
+		name = req.ID
+		var err error
+		accountID, err = r.client.accountID(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error resolving account",
+				"Could not determine the AWS account of the provider credentials: "+err.Error(),
+			)
+			return
+		}
+	}
+	if accountID == "" || name == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an access point name or account_id:name, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), accountID+":"+name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("account_id"), accountID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+}
+
+func (r *s3AccessPointResource) putPolicy(ctx context.Context, model s3AccessPointResourceModel) error {
+	_, err := r.client.S3ControlClient.PutAccessPointPolicy(ctx, &s3control.PutAccessPointPolicyInput{
+		AccountId: aws.String(model.AccountID.ValueString()),
+		Name:      aws.String(model.Name.ValueString()),
+		Policy:    aws.String(model.Policy.ValueString()),
+	})
+	return err
+}
+
+// refresh reads the access point's settings and computed attributes into model.
+func (r *s3AccessPointResource) refresh(ctx context.Context, model *s3AccessPointResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	out, err := r.client.S3ControlClient.GetAccessPoint(ctx, &s3control.GetAccessPointInput{
+		AccountId: aws.String(model.AccountID.ValueString()),
+		Name:      aws.String(model.Name.ValueString()),
+	})
+	if err != nil {
+		diags.AddError(
+			"Error reading access point",
+			"Could not read access point "+model.Name.ValueString()+": "+err.Error(),
+		)
+		return diags
+	}
+
+	model.ID = types.StringValue(model.AccountID.ValueString() + ":" + model.Name.ValueString())
+	model.Bucket = types.StringPointerValue(out.Bucket)
+	model.BucketAccountID = types.StringPointerValue(out.BucketAccountId)
+	model.Alias = types.StringPointerValue(out.Alias)
+	model.ARN = types.StringPointerValue(out.AccessPointArn)
+	model.NetworkOrigin = types.StringValue(string(out.NetworkOrigin))
+	model.VpcID = types.StringNull()
+	if out.VpcConfiguration != nil {
+		model.VpcID = types.StringPointerValue(out.VpcConfiguration.VpcId)
+	}
+	if block := out.PublicAccessBlockConfiguration; block != nil {
+		model.BlockPublicAcls = types.BoolValue(aws.ToBool(block.BlockPublicAcls))
+		model.BlockPublicPolicy = types.BoolValue(aws.ToBool(block.BlockPublicPolicy))
+		model.IgnorePublicAcls = types.BoolValue(aws.ToBool(block.IgnorePublicAcls))
+		model.RestrictPublicBuckets = types.BoolValue(aws.ToBool(block.RestrictPublicBuckets))
+	}
+	endpoints, d := types.MapValueFrom(ctx, types.StringType, out.Endpoints)
+	diags.Append(d...)
+	model.Endpoints = endpoints
+	return diags
+}
@@ -0,0 +1,233 @@
+package provider
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Access levels understood by the policy generator.
+const (
+	s3AccessListOnly  = "list-only"
+	s3AccessRead      = "read"
+	s3AccessWrite     = "write"
+	s3AccessReadWrite = "read-write"
+	s3AccessAdmin     = "admin"
+)
+
+// s3AccessLevels lists the access levels from least to most privileged.
+var s3AccessLevels = []string{s3AccessListOnly, s3AccessRead, s3AccessWrite, s3AccessReadWrite, s3AccessAdmin}
+
+var (
+	// s3LocationActions never carry s3:prefix, so they stay out of the prefix-limited
+	// ListBucket statement.
+	s3LocationActions    = []string{"s3:GetBucketLocation"}
+	s3ListActions        = []string{"s3:ListBucket"}
+	s3ReadObjectActions  = []string{"s3:GetObject", "s3:GetObjectTagging", "s3:GetObjectVersion"}
+	s3WriteBucketActions = []string{"s3:GetBucketLocation", "s3:ListBucketMultipartUploads"}
+	s3WriteObjectActions = []string{
+		"s3:AbortMultipartUpload",
+		"s3:DeleteObject",
+		"s3:ListMultipartUploadParts",
+		"s3:PutObject",
+		"s3:PutObjectTagging",
+	}
+	s3KMSReadActions = []string{"kms:Decrypt", "kms:DescribeKey"}
+	// s3KMSWriteActions include kms:Decrypt because S3 decrypts the data key of each part to
+	// complete a multipart upload.
+	s3KMSWriteActions = []string{"kms:Decrypt", "kms:DescribeKey", "kms:GenerateDataKey*"}
+)
+
+// s3AccessPolicyRequest describes the access an IAM policy should grant to a set of buckets.
+type s3AccessPolicyRequest struct {
+	// Buckets are bucket names or bucket ARNs.
+	Buckets []string
+	// Partition is the partition bucket names resolve to, the commercial aws partition
+	// when empty. Bucket ARNs keep their own partition.
+	Partition string
+	// Prefixes restrict object access, and listing, to keys under these prefixes.
+	Prefixes    []string
+	AccessLevel string
+	// RequireTLS only allows requests sent over TLS.
+	RequireTLS bool
+	// VPCEndpointIDs only allows requests arriving through these VPC endpoints.
+	VPCEndpointIDs []string
+	// KMSKeyARNs are the keys objects are encrypted with; the policy grants the KMS
+	// permissions the access level needs on them.
+	KMSKeyARNs []string
+}
+
+type iamPolicyDocument struct {
+	Version   string               `json:"Version"`
+	Statement []iamPolicyStatement `json:"Statement"`
+}
+
+type iamPolicyStatement struct {
+	Sid       string                    `json:"Sid"`
+	Effect    string                    `json:"Effect"`
+	Action    []string                  `json:"Action"`
+	Resource  []string                  `json:"Resource"`
+	Condition map[string]map[string]any `json:"Condition,omitempty"`
+}
+
+// buildS3AccessPolicy returns a least-privilege IAM policy document granting the requested
+// access level.
+func buildS3AccessPolicy(r s3AccessPolicyRequest) (string, error) {
+	if len(r.Buckets) == 0 {
+		return "", errors.New("at least one bucket is required")
+	}
+	bucketARNs := make([]string, 0, len(r.Buckets))
+	objectARNs := []string{}
+	for _, bucket := range r.Buckets {
+		arn, err := s3BucketARNFromNameOrARN(bucket, r.Partition)
+		if err != nil {
+			return "", err
+		}
+		bucketARNs = append(bucketARNs, arn)
+		if len(r.Prefixes) == 0 {
+			objectARNs = append(objectARNs, arn+"/*")
+		}
+		for _, prefix := range r.Prefixes {
+			objectARNs = append(objectARNs, arn+"/"+strings.TrimPrefix(prefix, "/")+"*")
+		}
+	}
+
+	conditions := map[string]map[string]any{}
+	if r.RequireTLS {
+		conditions["Bool"] = map[string]any{"aws:SecureTransport": "true"}
+	}
+	if len(r.VPCEndpointIDs) > 0 {
+		conditions["StringEquals"] = map[string]any{"aws:SourceVpce": r.VPCEndpointIDs}
+	}
+	// listConditions additionally limit ListBucket to the prefixes.
+	listConditions := conditions
+	if len(r.Prefixes) > 0 {
+		listConditions = map[string]map[string]any{}
+		for operator, values := range conditions {
+			listConditions[operator] = values
+		}
+		patterns := make([]string, 0, 2*len(r.Prefixes))
+		for _, prefix := range r.Prefixes {
+			prefix = strings.TrimPrefix(prefix, "/")
+			patterns = append(patterns, prefix, prefix+"*")
+		}
+		listConditions["StringLike"] = map[string]any{"s3:prefix": patterns}
+	}
+	statement := func(sid string, actions, resources []string, condition map[string]map[string]any) iamPolicyStatement {
+		if len(condition) == 0 {
+			condition = nil
+		}
+		return iamPolicyStatement{Sid: sid, Effect: "Allow", Action: actions, Resource: resources, Condition: condition}
+	}
+
+	var statements []iamPolicyStatement
+	var kmsActions []string
+	switch r.AccessLevel {
+	case s3AccessListOnly:
+		statements = append(statements,
+			statement("BucketLocation", s3LocationActions, bucketARNs, conditions),
+			statement("ListBucket", s3ListActions, bucketARNs, listConditions),
+		)
+	case s3AccessRead:
+		statements = append(statements,
+			statement("BucketLocation", s3LocationActions, bucketARNs, conditions),
+			statement("ListBucket", s3ListActions, bucketARNs, listConditions),
+			statement("ReadObjects", s3ReadObjectActions, objectARNs, conditions),
+		)
+		kmsActions = s3KMSReadActions
+	case s3AccessWrite:
+		statements = append(statements,
+			statement("BucketUploads", s3WriteBucketActions, bucketARNs, conditions),
+			statement("WriteObjects", s3WriteObjectActions, objectARNs, conditions),
+		)
+		kmsActions = s3KMSWriteActions
+	case s3AccessReadWrite:
+		statements = append(statements,
+			statement("ListBucket", s3ListActions, bucketARNs, listConditions),
+			statement("BucketUploads", s3WriteBucketActions, bucketARNs, conditions),
+			statement("ReadWriteObjects", mergeActions(s3ReadObjectActions, s3WriteObjectActions), objectARNs, conditions),
+		)
+		kmsActions = mergeActions(s3KMSReadActions, s3KMSWriteActions)
+	case s3AccessAdmin:
+		statements = append(statements,
+			statement("ManageBucket", []string{"s3:*"}, bucketARNs, conditions),
+			statement("ManageObjects", []string{"s3:*"}, objectARNs, conditions),
+		)
+		kmsActions = mergeActions(s3KMSReadActions, s3KMSWriteActions, []string{"kms:ReEncrypt*"})
+	default:
+		return "", fmt.Errorf("unsupported access level %q, expected one of %s", r.AccessLevel, strings.Join(s3AccessLevels, ", "))
+	}
+	if len(r.KMSKeyARNs) > 0 && len(kmsActions) > 0 {
+		statements = append(statements, statement("UseKMSKeys", kmsActions, r.KMSKeyARNs, s3KMSConditions(r)))
+	}
+
+	document, err := json.Marshal(iamPolicyDocument{Version: "2012-10-17", Statement: statements})
+	if err != nil {
+		return "", err
+	}
+	return string(document), nil
+}
+
+// s3KMSConditions limits the KMS statement to calls S3 makes on the caller's behalf. Those
+// forwarded calls carry no aws:SourceVpce, so the VPC endpoint condition is replaced by
+// kms:ViaService in the partition of each key.
+func s3KMSConditions(r s3AccessPolicyRequest) map[string]map[string]any {
+	conditions := map[string]map[string]any{}
+	if r.RequireTLS {
+		conditions["Bool"] = map[string]any{"aws:SecureTransport": "true"}
+	}
+	var services []string
+	for _, key := range r.KMSKeyARNs {
+		partition := "aws"
+		if parts := strings.SplitN(key, ":", 3); len(parts) == 3 && parts[0] == "arn" {
+			partition = parts[1]
+		}
+		services = mergeActions(services, []string{"s3.*." + awsPartitionDNSSuffix(partition)})
+	}
+	conditions["StringLike"] = map[string]any{"kms:ViaService": services}
+	return conditions
+}
+
+// s3BucketARNFromNameOrARN returns the ARN of a bucket given either its name or its ARN.
+// Names resolve to the given partition, the commercial aws partition when it is empty.
+func s3BucketARNFromNameOrARN(bucket, partition string) (string, error) {
+	if !strings.HasPrefix(bucket, "arn:") {
+		if bucket == "" || strings.ContainsAny(bucket, "/*") {
+			return "", fmt.Errorf("invalid bucket name %q", bucket)
+		}
+		if partition == "" {
+			partition = "aws"
+		}
+		return "arn:" + partition + ":s3:::" + bucket, nil
+	}
+	parts := strings.SplitN(bucket, ":", 6)
+	if len(parts) != 6 || parts[2] != "s3" || parts[3] != "" || parts[4] != "" || parts[5] == "" || strings.Contains(parts[5], "/") {
+		return "", fmt.Errorf("%q is not an S3 bucket ARN", bucket)
+	}
+	return bucket, nil
+}
+
+// s3ClientPartition returns the partition of the provider region, which bucket names in
+// generated policies resolve to. Without an AWS client that is the commercial aws partition.
+func s3ClientPartition(client *ClientS3) string {
+	if client == nil {
+		return "aws"
+	}
+	return awsPartition(client.Region)
+}
+
+// mergeActions concatenates action lists, dropping duplicates while keeping order.
+func mergeActions(lists ...[]string) []string {
+	seen := map[string]bool{}
+	var merged []string
+	for _, list := range lists {
+		for _, action := range list {
+			if !seen[action] {
+				seen[action] = true
+				merged = append(merged, action)
+			}
+		}
+	}
+	return merged
+}
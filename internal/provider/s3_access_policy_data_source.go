@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &s3AccessPolicyDataSource{}
+	_ datasource.DataSourceWithConfigure = &s3AccessPolicyDataSource{}
+)
+
+// NewS3AccessPolicyDataSource is a helper function to simplify the provider implementation.
+func NewS3AccessPolicyDataSource() datasource.DataSource {
+	return &s3AccessPolicyDataSource{}
+}
+
+// s3AccessPolicyDataSource renders an IAM policy document granting access to buckets. It
+// makes no API calls; the client only decides the partition bucket names resolve to.
+type s3AccessPolicyDataSource struct {
+	client *ClientS3
+}
+
+type s3AccessPolicyDataSourceModel struct {
+	Buckets        []string     `tfsdk:"buckets"`
+	Prefixes       []string     `tfsdk:"prefixes"`
+	AccessLevel    types.String `tfsdk:"access_level"`
+	RequireTLS     types.Bool   `tfsdk:"require_tls"`
+	VPCEndpointIDs []string     `tfsdk:"vpc_endpoint_ids"`
+	KMSKeyARNs     []string     `tfsdk:"kms_key_arns"`
+	JSON           types.String `tfsdk:"json"`
+}
+
+// Metadata returns the data source type name.
+func (d *s3AccessPolicyDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_access_policy"
+}
+
+// Schema defines the schema for the data source.
+func (d *s3AccessPolicyDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Generates a least-privilege IAM policy document for access to S3 buckets.",
+		Attributes: map[string]schema.Attribute{
+			"buckets": schema.ListAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "Bucket names or bucket ARNs. Names resolve to the partition of the provider region, the commercial aws partition when the provider is not configured for AWS.",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"prefixes": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Restrict object access and listing to keys under these prefixes.",
+			},
+			"access_level": schema.StringAttribute{
+				Required:    true,
+				Description: "One of list-only, read, write, read-write or admin.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(s3AccessLevels...),
+				},
+			},
+			"require_tls": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Only allow requests sent over TLS.",
+			},
+			"vpc_endpoint_ids": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Only allow requests arriving through these VPC endpoints.",
+			},
+			"kms_key_arns": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "KMS keys the objects are encrypted with. The policy grants the key permissions the access level needs.",
+			},
+			"json": schema.StringAttribute{
+				Computed:    true,
+				Description: "The policy document.",
+			},
+		},
+	}
+}
+
+// Read renders the policy document.
+func (d *s3AccessPolicyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state s3AccessPolicyDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	document, err := buildS3AccessPolicy(s3AccessPolicyRequest{
+		Buckets:        state.Buckets,
+		Partition:      s3ClientPartition(d.client),
+		Prefixes:       state.Prefixes,
+		AccessLevel:    state.AccessLevel.ValueString(),
+		RequireTLS:     state.RequireTLS.ValueBool(),
+		VPCEndpointIDs: state.VPCEndpointIDs,
+		KMSKeyARNs:     state.KMSKeyARNs,
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("unable to generate policy", err.Error())
+		return
+	}
+	state.JSON = types.StringValue(document)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Configure adds the provider configured client to the data source. Other clouds leave it
+// nil, since the policy can be rendered without one.
+func (d *s3AccessPolicyDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, _ *datasource.ConfigureResponse) {
+	if client, ok := req.ProviderData.(*ClientS3); ok {
+		d.client = client
+	}
+}
@@ -0,0 +1,124 @@
+package provider
+
+import (
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &s3AccessPolicyFunction{}
+
+// s3AccessPolicyOptions are the keys accepted in the options argument.
+var s3AccessPolicyOptions = []string{"kms_key_arns", "partition", "require_tls", "vpc_endpoint_ids"}
+
+// NewS3AccessPolicyFunction is a helper function to simplify the provider implementation.
+func NewS3AccessPolicyFunction() function.Function {
+	return &s3AccessPolicyFunction{}
+}
+
+// s3AccessPolicyFunction is the function counterpart of the xsynchco_s3_access_policy
+// data source.
+type s3AccessPolicyFunction struct{}
+
+// Metadata returns the function name.
+func (f *s3AccessPolicyFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "s3_access_policy"
+}
+
+// Definition defines the parameters and return type of the function.
+func (f *s3AccessPolicyFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Generates a least-privilege IAM policy document for access to S3 buckets.",
+		Description: "Returns the same policy JSON as the xsynchco_s3_access_policy data source.",
+		Parameters: []function.Parameter{
+			function.ListParameter{
+				Name:        "buckets",
+				ElementType: types.StringType,
+				Description: "Bucket names or bucket ARNs. Names resolve to the commercial aws partition unless the partition option is set.",
+			},
+			function.ListParameter{
+				Name:           "prefixes",
+				ElementType:    types.StringType,
+				AllowNullValue: true,
+				Description:    "Key prefixes to restrict access to, or null for whole buckets.",
+			},
+			function.StringParameter{
+				Name:        "access_level",
+				Description: "One of " + strings.Join(s3AccessLevels, ", ") + ".",
+			},
+			function.MapParameter{
+				Name:           "options",
+				ElementType:    types.StringType,
+				AllowNullValue: true,
+				Description: "Optional conditions: require_tls (\"true\" or \"false\"), and vpc_endpoint_ids " +
+					"and kms_key_arns as comma-separated lists. partition sets the partition bucket names resolve to, " +
+					"for example aws-cn or aws-us-gov, and defaults to aws.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run renders the policy document.
+func (f *s3AccessPolicyFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var (
+		buckets, prefixes []string
+		accessLevel       string
+		options           map[string]string
+	)
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &buckets, &prefixes, &accessLevel, &options))
+	if resp.Error != nil {
+		return
+	}
+
+	for name := range options {
+		if !slices.Contains(s3AccessPolicyOptions, name) {
+			resp.Error = function.NewArgumentFuncError(3, "Unsupported option "+name+", expected one of "+strings.Join(s3AccessPolicyOptions, ", "))
+			return
+		}
+	}
+	request := s3AccessPolicyRequest{
+		Buckets:        buckets,
+		Prefixes:       prefixes,
+		AccessLevel:    accessLevel,
+		VPCEndpointIDs: splitOptionList(options["vpc_endpoint_ids"]),
+		KMSKeyARNs:     splitOptionList(options["kms_key_arns"]),
+		Partition:      options["partition"],
+	}
+	if partition, ok := options["partition"]; ok && !slices.Contains(awsPartitions, partition) {
+		resp.Error = function.NewArgumentFuncError(3, "partition must be one of "+strings.Join(awsPartitions, ", "))
+		return
+	}
+	if value, ok := options["require_tls"]; ok {
+		requireTLS, err := strconv.ParseBool(value)
+		if err != nil {
+			resp.Error = function.NewArgumentFuncError(3, "require_tls must be true or false")
+			return
+		}
+		request.RequireTLS = requireTLS
+	}
+
+	document, err := buildS3AccessPolicy(request)
+	if err != nil {
+		resp.Error = function.NewFuncError("Could not generate policy: " + err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, document))
+}
+
+// splitOptionList splits a comma-separated option value, ignoring blank entries.
+func splitOptionList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestBuildS3AccessPolicyRead(t *testing.T) {
+	document, err := buildS3AccessPolicy(s3AccessPolicyRequest{
+		Buckets:     []string{"data", "arn:aws-cn:s3:::logs"},
+		Prefixes:    []string{"team/"},
+		AccessLevel: s3AccessRead,
+		RequireTLS:  true,
+		KMSKeyARNs:  []string{"arn:aws:kms:eu-west-1:111122223333:key/abc"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var policy iamPolicyDocument
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		t.Fatal(err)
+	}
+	if len(policy.Statement) != 4 {
+		t.Fatalf("got %d statements, want location, list, read and KMS: %s", len(policy.Statement), document)
+	}
+	list, read, kms := policy.Statement[1], policy.Statement[2], policy.Statement[3]
+	if list.Resource[0] != "arn:aws:s3:::data" || list.Resource[1] != "arn:aws-cn:s3:::logs" {
+		t.Errorf("list resources = %v", list.Resource)
+	}
+	if list.Condition["StringLike"] == nil || list.Condition["Bool"] == nil {
+		t.Errorf("list conditions = %v, want prefix and TLS conditions", list.Condition)
+	}
+	if read.Resource[0] != "arn:aws:s3:::data/team/*" || read.Condition["StringLike"] != nil {
+		t.Errorf("read statement = %+v", read)
+	}
+	if kms.Action[0] != "kms:Decrypt" {
+		t.Errorf("KMS actions = %v", kms.Action)
+	}
+}
+
+func TestBuildS3AccessPolicyErrors(t *testing.T) {
+	cases := map[string]s3AccessPolicyRequest{
+		"no buckets":  {AccessLevel: s3AccessRead},
+		"bad level":   {Buckets: []string{"data"}, AccessLevel: "owner"},
+		"object ARN":  {Buckets: []string{"arn:aws:s3:::data/key"}, AccessLevel: s3AccessRead},
+		"non-S3 ARN":  {Buckets: []string{"arn:aws:sqs:eu-west-1:111122223333:queue"}, AccessLevel: s3AccessRead},
+		"bucket glob": {Buckets: []string{"data*"}, AccessLevel: s3AccessRead},
+	}
+	for name, request := range cases {
+		if _, err := buildS3AccessPolicy(request); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestBuildS3AccessPolicyListOnly(t *testing.T) {
+	document, err := buildS3AccessPolicy(s3AccessPolicyRequest{
+		Buckets:     []string{"data"},
+		AccessLevel: s3AccessListOnly,
+		KMSKeyARNs:  []string{"arn:aws:kms:eu-west-1:111122223333:key/abc"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Version":"2012-10-17","Statement":[{"Sid":"BucketLocation","Effect":"Allow","Action":["s3:GetBucketLocation"],"Resource":["arn:aws:s3:::data"]},{"Sid":"ListBucket","Effect":"Allow","Action":["s3:ListBucket"],"Resource":["arn:aws:s3:::data"]}]}`
+	if document != want {
+		t.Errorf("policy = %s, want %s", document, want)
+	}
+}
+
+func TestBuildS3AccessPolicyPartition(t *testing.T) {
+	document, err := buildS3AccessPolicy(s3AccessPolicyRequest{
+		Buckets:     []string{"data", "arn:aws:s3:::logs"},
+		Partition:   "aws-us-gov",
+		AccessLevel: s3AccessListOnly,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var policy iamPolicyDocument
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"arn:aws-us-gov:s3:::data", "arn:aws:s3:::logs"}; !reflect.DeepEqual(policy.Statement[0].Resource, want) {
+		t.Errorf("resources = %v, want %v", policy.Statement[0].Resource, want)
+	}
+	if partition := s3ClientPartition(&ClientS3{Region: "cn-north-1"}); partition != "aws-cn" {
+		t.Errorf("partition of cn-north-1 = %s", partition)
+	}
+}
+
+func TestBuildS3AccessPolicyWriteKMSActions(t *testing.T) {
+	document, err := buildS3AccessPolicy(s3AccessPolicyRequest{
+		Buckets:     []string{"data"},
+		AccessLevel: s3AccessWrite,
+		KMSKeyARNs:  []string{"arn:aws:kms:eu-west-1:111122223333:key/abc"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var policy iamPolicyDocument
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		t.Fatal(err)
+	}
+	kms := policy.Statement[len(policy.Statement)-1]
+	if want := []string{"kms:Decrypt", "kms:DescribeKey", "kms:GenerateDataKey*"}; !reflect.DeepEqual(kms.Action, want) {
+		t.Errorf("write KMS actions = %v, want %v", kms.Action, want)
+	}
+}
+
+func TestBuildS3AccessPolicyPrefixLayout(t *testing.T) {
+	for _, level := range []string{s3AccessListOnly, s3AccessRead, s3AccessWrite, s3AccessReadWrite} {
+		document, err := buildS3AccessPolicy(s3AccessPolicyRequest{
+			Buckets:     []string{"data"},
+			Prefixes:    []string{"team/"},
+			AccessLevel: level,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var policy iamPolicyDocument
+		if err := json.Unmarshal([]byte(document), &policy); err != nil {
+			t.Fatal(err)
+		}
+
+		var location, list []iamPolicyStatement
+		for _, statement := range policy.Statement {
+			if slices.Contains(statement.Action, "s3:GetBucketLocation") {
+				location = append(location, statement)
+			}
+			if slices.Contains(statement.Action, "s3:ListBucket") {
+				list = append(list, statement)
+			}
+		}
+		if len(location) != 1 || location[0].Condition["StringLike"] != nil {
+			t.Errorf("%s: GetBucketLocation must be granted once without the s3:prefix condition: %s", level, document)
+		}
+		if level == s3AccessWrite {
+			if len(list) != 0 {
+				t.Errorf("%s: ListBucket granted: %s", level, document)
+			}
+			continue
+		}
+		if len(list) != 1 || len(list[0].Action) != 1 || list[0].Condition["StringLike"]["s3:prefix"] == nil {
+			t.Errorf("%s: ListBucket must be alone in a statement limited by s3:prefix: %s", level, document)
+		}
+	}
+}
+
+func TestBuildS3AccessPolicyKMSThroughVPCEndpoint(t *testing.T) {
+	document, err := buildS3AccessPolicy(s3AccessPolicyRequest{
+		Buckets:        []string{"data"},
+		AccessLevel:    s3AccessReadWrite,
+		RequireTLS:     true,
+		VPCEndpointIDs: []string{"vpce-0123456789abcdef0"},
+		KMSKeyARNs:     []string{"arn:aws-cn:kms:cn-north-1:111122223333:key/abc"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var policy iamPolicyDocument
+	if err := json.Unmarshal([]byte(document), &policy); err != nil {
+		t.Fatal(err)
+	}
+	kms := policy.Statement[len(policy.Statement)-1]
+	if kms.Sid != "UseKMSKeys" {
+		t.Fatalf("last statement is %s, want UseKMSKeys: %s", kms.Sid, document)
+	}
+	if _, ok := kms.Condition["StringEquals"]["aws:SourceVpce"]; ok {
+		t.Errorf("KMS statement requires aws:SourceVpce, which S3's forwarded KMS calls never carry: %v", kms.Condition)
+	}
+	if via := kms.Condition["StringLike"]["kms:ViaService"]; !reflect.DeepEqual(via, []any{"s3.*.amazonaws.com.cn"}) {
+		t.Errorf("kms:ViaService = %v", via)
+	}
+	if kms.Condition["Bool"] == nil {
+		t.Errorf("KMS statement lost the TLS condition: %v", kms.Condition)
+	}
+	if policy.Statement[0].Condition["StringEquals"]["aws:SourceVpce"] == nil {
+		t.Errorf("S3 statements lost the VPC endpoint condition: %v", policy.Statement[0].Condition)
+	}
+}
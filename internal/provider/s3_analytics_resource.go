@@ -0,0 +1,354 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &s3AnalyticsResource{}
+	_ resource.ResourceWithConfigure   = &s3AnalyticsResource{}
+	_ resource.ResourceWithImportState = &s3AnalyticsResource{}
+)
+
+type s3AnalyticsResourceModel struct {
+	ID             types.String       `tfsdk:"id"`
+	Last_Updated   types.String       `tfsdk:"last_updated"`
+	Bucket         types.String       `tfsdk:"bucket"`
+	Configurations []s3AnalyticsModel `tfsdk:"configurations"`
+}
+
+// s3AnalyticsModel is one storage class analysis configuration.
+type s3AnalyticsModel struct {
+	ID     types.String            `tfsdk:"id"`
+	Prefix types.String            `tfsdk:"prefix"`
+	Tags   map[string]string       `tfsdk:"tags"`
+	Export *s3AnalyticsExportModel `tfsdk:"export"`
+}
+
+// s3AnalyticsExportModel is where the daily analysis results are exported to.
+type s3AnalyticsExportModel struct {
+	BucketARN       types.String `tfsdk:"bucket_arn"`
+	BucketAccountID types.String `tfsdk:"bucket_account_id"`
+	Prefix          types.String `tfsdk:"prefix"`
+	Format          types.String `tfsdk:"format"`
+}
+
+// NewS3AnalyticsResource is a helper function to simplify the provider implementation.
+func NewS3AnalyticsResource() resource.Resource {
+	return &s3AnalyticsResource{}
+}
+
+// s3AnalyticsResource owns every analytics configuration of one bucket.
+type s3AnalyticsResource struct {
+	client *ClientS3
+}
+
+func (r *s3AnalyticsResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the developer", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *s3AnalyticsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_bucket_analytics"
+}
+
+// Schema defines the schema for the resource.
+func (r *s3AnalyticsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the storage class analysis configurations of an S3 bucket. " +
+			"Configurations on the bucket that are not listed here are removed.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"bucket": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"configurations": schema.ListNestedAttribute{
+				Required: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Required:    true,
+							Description: "Name of the configuration, unique within the bucket.",
+						},
+						"prefix": schema.StringAttribute{
+							Optional:    true,
+							Description: "Only analyze objects whose keys start with this prefix.",
+						},
+						"tags": schema.MapAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Only analyze objects carrying all of these tags.",
+						},
+						"export": schema.SingleNestedAttribute{
+							Optional:    true,
+							Description: "Export the analysis results as CSV to another bucket.",
+							Attributes: map[string]schema.Attribute{
+								"bucket_arn": schema.StringAttribute{
+									Required:    true,
+									Description: "ARN of the bucket the results are written to.",
+									Validators: []validator.String{
+										stringvalidator.RegexMatches(regexp.MustCompile(`^arn:[^:]+:s3:::.+`), "must be an S3 bucket ARN"),
+									},
+								},
+								"bucket_account_id": schema.StringAttribute{
+									Optional:    true,
+									Description: "Expected owner of the destination bucket.",
+								},
+								"prefix": schema.StringAttribute{
+									Optional:    true,
+									Description: "Key prefix of the exported files.",
+								},
+								"format": schema.StringAttribute{
+									Optional:    true,
+									Computed:    true,
+									Default:     stringdefault.StaticString(string(awstypes.AnalyticsS3ExportFileFormatCsv)),
+									Description: "Export file format. S3 only supports CSV.",
+									Validators: []validator.String{
+										stringvalidator.OneOf(string(awstypes.AnalyticsS3ExportFileFormatCsv)),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *s3AnalyticsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan s3AnalyticsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &plan, resp.Diagnostics.AddError)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *s3AnalyticsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state s3AnalyticsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	configurations, err := r.configurations().read(ctx, state.Bucket.ValueString(), state.Configurations)
+	if err != nil {
+		if isS3ErrorCode(err, "NoSuchBucket") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading analytics configurations",
+			"Could not list analytics configurations for bucket "+state.Bucket.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.ID = state.Bucket
+	state.Configurations = make([]s3AnalyticsModel, 0, len(configurations))
+	for _, item := range configurations {
+		state.Configurations = append(state.Configurations, flattenS3Analytics(item))
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *s3AnalyticsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan s3AnalyticsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &plan, resp.Diagnostics.AddError)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *s3AnalyticsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state s3AnalyticsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.configurations().deleteAll(ctx, state.Bucket.ValueString(), state.Configurations, resp.Diagnostics.AddError)
+}
+
+// ImportState imports the analytics configurations of an existing bucket by bucket name.
+func (r *s3AnalyticsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), req.ID)...)
+}
+
+// write puts every planned configuration and deletes any other configuration on the bucket.
+func (r *s3AnalyticsResource) write(ctx context.Context, plan *s3AnalyticsResourceModel, addError func(string, string)) {
+	r.configurations().write(ctx, plan.Bucket.ValueString(), plan.Configurations, addError)
+	plan.ID = plan.Bucket
+	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
+}
+
+// configurations returns the operations on the analytics configurations of a bucket.
+func (r *s3AnalyticsResource) configurations() s3BucketConfigurationSet[s3AnalyticsModel, awstypes.AnalyticsConfiguration] {
+	svc := r.client.S3Client
+	return s3BucketConfigurationSet[s3AnalyticsModel, awstypes.AnalyticsConfiguration]{
+		kind:    "analytics",
+		modelID: func(item s3AnalyticsModel) string { return item.ID.ValueString() },
+		id:      func(config awstypes.AnalyticsConfiguration) string { return aws.ToString(config.Id) },
+		put: func(ctx context.Context, bucket string, item s3AnalyticsModel) error {
+			_, err := svc.PutBucketAnalyticsConfiguration(ctx, &s3.PutBucketAnalyticsConfigurationInput{
+				Bucket:                 aws.String(bucket),
+				Id:                     aws.String(item.ID.ValueString()),
+				AnalyticsConfiguration: expandS3Analytics(item),
+			})
+			return err
+		},
+		delete: func(ctx context.Context, bucket, id string) error {
+			_, err := svc.DeleteBucketAnalyticsConfiguration(ctx, &s3.DeleteBucketAnalyticsConfigurationInput{
+				Bucket: aws.String(bucket),
+				Id:     aws.String(id),
+			})
+			return err
+		},
+		list: func(ctx context.Context, bucket string) ([]awstypes.AnalyticsConfiguration, error) {
+			return listS3AnalyticsConfigurations(ctx, svc, bucket)
+		},
+	}
+}
+
+// listS3AnalyticsConfigurations returns every analytics configuration on the bucket.
+func listS3AnalyticsConfigurations(ctx context.Context, svc *s3.Client, bucket string) ([]awstypes.AnalyticsConfiguration, error) {
+	var configurations []awstypes.AnalyticsConfiguration
+	input := &s3.ListBucketAnalyticsConfigurationsInput{Bucket: aws.String(bucket)}
+	for {
+		out, err := svc.ListBucketAnalyticsConfigurations(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		configurations = append(configurations, out.AnalyticsConfigurationList...)
+		if !aws.ToBool(out.IsTruncated) || out.NextContinuationToken == nil {
+			return configurations, nil
+		}
+		input.ContinuationToken = out.NextContinuationToken
+	}
+}
+
+func expandS3Analytics(item s3AnalyticsModel) *awstypes.AnalyticsConfiguration {
+	config := &awstypes.AnalyticsConfiguration{
+		Id:                   aws.String(item.ID.ValueString()),
+		StorageClassAnalysis: &awstypes.StorageClassAnalysis{},
+	}
+	if item.Export != nil {
+		config.StorageClassAnalysis.DataExport = &awstypes.StorageClassAnalysisDataExport{
+			OutputSchemaVersion: awstypes.StorageClassAnalysisSchemaVersionV1,
+			Destination: &awstypes.AnalyticsExportDestination{
+				S3BucketDestination: &awstypes.AnalyticsS3BucketDestination{
+					Bucket:          aws.String(item.Export.BucketARN.ValueString()),
+					BucketAccountId: item.Export.BucketAccountID.ValueStringPointer(),
+					Prefix:          item.Export.Prefix.ValueStringPointer(),
+					Format:          awstypes.AnalyticsS3ExportFileFormat(item.Export.Format.ValueString()),
+				},
+			},
+		}
+	}
+
+	tags := s3TagsFromMap(item.Tags)
+	switch {
+	case len(tags) > 1 || (len(tags) == 1 && !item.Prefix.IsNull()):
+		config.Filter = &awstypes.AnalyticsFilterMemberAnd{
+			Value: awstypes.AnalyticsAndOperator{Prefix: item.Prefix.ValueStringPointer(), Tags: tags},
+		}
+	case len(tags) == 1:
+		config.Filter = &awstypes.AnalyticsFilterMemberTag{Value: tags[0]}
+	case !item.Prefix.IsNull():
+		config.Filter = &awstypes.AnalyticsFilterMemberPrefix{Value: item.Prefix.ValueString()}
+	}
+	return config
+}
+
+func flattenS3Analytics(config awstypes.AnalyticsConfiguration) s3AnalyticsModel {
+	item := s3AnalyticsModel{
+		ID:     types.StringPointerValue(config.Id),
+		Prefix: types.StringNull(),
+	}
+	if analysis := config.StorageClassAnalysis; analysis != nil && analysis.DataExport != nil &&
+		analysis.DataExport.Destination != nil && analysis.DataExport.Destination.S3BucketDestination != nil {
+		destination := analysis.DataExport.Destination.S3BucketDestination
+		item.Export = &s3AnalyticsExportModel{
+			BucketARN:       types.StringPointerValue(destination.Bucket),
+			BucketAccountID: types.StringPointerValue(destination.BucketAccountId),
+			Prefix:          types.StringPointerValue(destination.Prefix),
+			Format:          types.StringValue(string(destination.Format)),
+		}
+	}
+	switch filter := config.Filter.(type) {
+	case *awstypes.AnalyticsFilterMemberAnd:
+		item.Prefix = types.StringPointerValue(filter.Value.Prefix)
+		item.Tags = s3TagsToMap(filter.Value.Tags)
+	case *awstypes.AnalyticsFilterMemberTag:
+		item.Tags = s3TagsToMap([]awstypes.Tag{filter.Value})
+	case *awstypes.AnalyticsFilterMemberPrefix:
+		item.Prefix = types.StringValue(filter.Value)
+	}
+	return item
+}
@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestS3AnalyticsRoundTrip(t *testing.T) {
+	export := &s3AnalyticsExportModel{
+		BucketARN:       types.StringValue("arn:aws:s3:::reports"),
+		BucketAccountID: types.StringValue("111122223333"),
+		Prefix:          types.StringValue("analytics/"),
+		Format:          types.StringValue(string(awstypes.AnalyticsS3ExportFileFormatCsv)),
+	}
+	cases := map[string]struct {
+		item       s3AnalyticsModel
+		wantFilter awstypes.AnalyticsFilter
+	}{
+		"whole bucket": {
+			item:       s3AnalyticsModel{ID: types.StringValue("all"), Prefix: types.StringNull()},
+			wantFilter: nil,
+		},
+		"prefix with export": {
+			item:       s3AnalyticsModel{ID: types.StringValue("logs"), Prefix: types.StringValue("logs/"), Export: export},
+			wantFilter: &awstypes.AnalyticsFilterMemberPrefix{},
+		},
+		"single tag": {
+			item:       s3AnalyticsModel{ID: types.StringValue("hot"), Prefix: types.StringNull(), Tags: map[string]string{"tier": "hot"}},
+			wantFilter: &awstypes.AnalyticsFilterMemberTag{},
+		},
+		"prefix and tags": {
+			item: s3AnalyticsModel{
+				ID:     types.StringValue("team"),
+				Prefix: types.StringValue("team/"),
+				Tags:   map[string]string{"team": "data", "tier": "cold"},
+			},
+			wantFilter: &awstypes.AnalyticsFilterMemberAnd{},
+		},
+		"export without prefix": {
+			item: s3AnalyticsModel{
+				ID:     types.StringValue("export"),
+				Prefix: types.StringNull(),
+				Export: &s3AnalyticsExportModel{
+					BucketARN:       types.StringValue("arn:aws:s3:::reports"),
+					BucketAccountID: types.StringNull(),
+					Prefix:          types.StringNull(),
+					Format:          types.StringValue(string(awstypes.AnalyticsS3ExportFileFormatCsv)),
+				},
+			},
+			wantFilter: nil,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			config := expandS3Analytics(tc.item)
+			if reflect.TypeOf(config.Filter) != reflect.TypeOf(tc.wantFilter) {
+				t.Fatalf("filter type = %T, want %T", config.Filter, tc.wantFilter)
+			}
+			if got := flattenS3Analytics(*config); !reflect.DeepEqual(got, tc.item) {
+				t.Errorf("round trip changed the configuration:\ngot  %+v\nwant %+v", got, tc.item)
+			}
+		})
+	}
+}
@@ -0,0 +1,888 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	iamtypes "github.com/aws/aws-sdk-go-v2/service/iam/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &s3BucketAccessResource{}
+	_ resource.ResourceWithConfigure   = &s3BucketAccessResource{}
+	_ resource.ResourceWithModifyPlan  = &s3BucketAccessResource{}
+	_ resource.ResourceWithImportState = &s3BucketAccessResource{}
+)
+
+const (
+	iamPrincipalRole = "role"
+	iamPrincipalUser = "user"
+
+	s3BucketAccessDefaultPolicyName = "xsynchco-s3-access"
+)
+
+type s3BucketAccessResourceModel struct {
+	ID               types.String `tfsdk:"id"`
+	Last_Updated     types.String `tfsdk:"last_updated"`
+	Name             types.String `tfsdk:"name"`
+	PrincipalType    types.String `tfsdk:"principal_type"`
+	Path             types.String `tfsdk:"path"`
+	AssumeRolePolicy types.String `tfsdk:"assume_role_policy"`
+	Buckets          types.List   `tfsdk:"buckets"`
+	Prefixes         types.List   `tfsdk:"prefixes"`
+	AccessLevel      types.String `tfsdk:"access_level"`
+	RequireTLS       types.Bool   `tfsdk:"require_tls"`
+	VPCEndpointIDs   types.List   `tfsdk:"vpc_endpoint_ids"`
+	KMSKeyARNs       types.List   `tfsdk:"kms_key_arns"`
+	PolicyName       types.String `tfsdk:"policy_name"`
+	Policy           types.String `tfsdk:"policy"`
+	CreateAccessKey  types.Bool   `tfsdk:"create_access_key"`
+	AccessKeyID      types.String `tfsdk:"access_key_id"`
+	SecretAccessKey  types.String `tfsdk:"secret_access_key"`
+	Tags             types.Map    `tfsdk:"tags"`
+	ARN              types.String `tfsdk:"arn"`
+	UniqueID         types.String `tfsdk:"unique_id"`
+}
+
+// NewS3BucketAccessResource is a helper function to simplify the provider implementation.
+func NewS3BucketAccessResource() resource.Resource {
+	return &s3BucketAccessResource{}
+}
+
+// s3BucketAccessResource manages an IAM role or user whose only permissions are an inline
+// policy granting access to a set of buckets.
+type s3BucketAccessResource struct {
+	client *ClientS3
+}
+
+func (r *s3BucketAccessResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the developer", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *s3BucketAccessResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_bucket_access"
+}
+
+// Schema defines the schema for the resource.
+func (r *s3BucketAccessResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	requiresReplace := []planmodifier.String{
+		stringplanmodifier.RequiresReplace(),
+	}
+	computed := []planmodifier.String{
+		stringplanmodifier.UseStateForUnknown(),
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Manages an IAM role or user with an inline least-privilege policy for a set of buckets, " +
+			"and optionally an access key for the user.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:      true,
+				Description:   "principal_type/name, for example role/app-data.",
+				PlanModifiers: computed,
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"name": schema.StringAttribute{
+				Required:      true,
+				Description:   "Name of the IAM role or user.",
+				PlanModifiers: requiresReplace,
+			},
+			"principal_type": schema.StringAttribute{
+				Optional:      true,
+				Computed:      true,
+				Default:       stringdefault.StaticString(iamPrincipalRole),
+				Description:   "role or user.",
+				PlanModifiers: requiresReplace,
+				Validators: []validator.String{
+					stringvalidator.OneOf(iamPrincipalRole, iamPrincipalUser),
+				},
+			},
+			"path": schema.StringAttribute{
+				Optional:      true,
+				Computed:      true,
+				Default:       stringdefault.StaticString("/"),
+				PlanModifiers: requiresReplace,
+			},
+			"assume_role_policy": schema.StringAttribute{
+				Optional:    true,
+				Description: "JSON trust policy of the role. Required for roles, not allowed for users.",
+			},
+			"buckets": schema.ListAttribute{
+				Required:    true,
+				ElementType: types.StringType,
+				Description: "Bucket names or bucket ARNs to grant access to. Names resolve to the partition of the provider region.",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+			"prefixes": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Restrict object access and listing to keys under these prefixes.",
+			},
+			"access_level": schema.StringAttribute{
+				Required:    true,
+				Description: "One of list-only, read, write, read-write or admin.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(s3AccessLevels...),
+				},
+			},
+			"require_tls": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Only allow requests sent over TLS.",
+			},
+			"vpc_endpoint_ids": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Only allow requests arriving through these VPC endpoints.",
+			},
+			"kms_key_arns": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "KMS keys the objects are encrypted with.",
+			},
+			"policy_name": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(s3BucketAccessDefaultPolicyName),
+				Description: "Name of the inline policy.",
+			},
+			"policy": schema.StringAttribute{
+				Computed:    true,
+				Description: "The generated inline policy document.",
+			},
+			"create_access_key": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Issue an access key for the user. Only allowed for users.",
+			},
+			"access_key_id": schema.StringAttribute{
+				Computed: true,
+			},
+			"secret_access_key": schema.StringAttribute{
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Secret of the access key. Only known to the state that created the key.",
+			},
+			"tags": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"arn": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: computed,
+			},
+			"unique_id": schema.StringAttribute{
+				Computed:      true,
+				PlanModifiers: computed,
+			},
+		},
+	}
+}
+
+// ModifyPlan checks the trust policy and access key settings against the principal type and
+// renders the inline policy, so that it is shown in the plan and drift is corrected.
+func (r *s3BucketAccessResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan s3BucketAccessResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.PrincipalType.IsUnknown() {
+		isRole := plan.PrincipalType.ValueString() == iamPrincipalRole
+		if isRole && plan.AssumeRolePolicy.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("assume_role_policy"), "Missing Trust Policy",
+				"assume_role_policy is required when principal_type is role.")
+		}
+		if !isRole && !plan.AssumeRolePolicy.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("assume_role_policy"), "Unexpected Trust Policy",
+				"assume_role_policy can only be set when principal_type is role.")
+		}
+		if isRole && plan.CreateAccessKey.ValueBool() {
+			resp.Diagnostics.AddAttributeError(path.Root("create_access_key"), "Access Keys Require a User",
+				"Access keys can only be issued for principal_type user.")
+		}
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	request, known, diags := s3BucketAccessPolicyRequest(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	request.Partition = s3ClientPartition(r.client)
+	plan.Policy = types.StringUnknown()
+	if known {
+		document, err := buildS3AccessPolicy(request)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid Bucket Access", err.Error())
+			return
+		}
+		plan.Policy = types.StringValue(document)
+	}
+
+	var state *s3BucketAccessResourceModel
+	if !req.State.Raw.IsNull() {
+		state = &s3BucketAccessResourceModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	switch {
+	case !plan.CreateAccessKey.ValueBool():
+		plan.AccessKeyID = types.StringNull()
+		plan.SecretAccessKey = types.StringNull()
+	case state != nil && !state.AccessKeyID.IsNull():
+		plan.AccessKeyID = state.AccessKeyID
+		plan.SecretAccessKey = state.SecretAccessKey
+	default:
+		plan.AccessKeyID = types.StringUnknown()
+		plan.SecretAccessKey = types.StringUnknown()
+	}
+	if state == nil || !plan.Policy.Equal(state.Policy) || !plan.AccessKeyID.Equal(state.AccessKeyID) {
+		plan.Last_Updated = types.StringUnknown()
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *s3BucketAccessResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan s3BucketAccessResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tags, diags := iamTagsFromMap(ctx, plan.Tags)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := plan.Name.ValueString()
+	svc := r.client.IAMClient
+	var err error
+	if plan.PrincipalType.ValueString() == iamPrincipalRole {
+		var out *iam.CreateRoleOutput
+		out, err = svc.CreateRole(ctx, &iam.CreateRoleInput{
+			RoleName:                 aws.String(name),
+			Path:                     aws.String(plan.Path.ValueString()),
+			AssumeRolePolicyDocument: aws.String(plan.AssumeRolePolicy.ValueString()),
+			Tags:                     tags,
+		})
+		if err == nil {
+			plan.ARN = types.StringPointerValue(out.Role.Arn)
+			plan.UniqueID = types.StringPointerValue(out.Role.RoleId)
+		}
+	} else {
+		var out *iam.CreateUserOutput
+		out, err = svc.CreateUser(ctx, &iam.CreateUserInput{
+			UserName: aws.String(name),
+			Path:     aws.String(plan.Path.ValueString()),
+			Tags:     tags,
+		})
+		if err == nil {
+			plan.ARN = types.StringPointerValue(out.User.Arn)
+			plan.UniqueID = types.StringPointerValue(out.User.UserId)
+		}
+	}
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error creating IAM "+plan.PrincipalType.ValueString(),
+			"Could not create "+plan.PrincipalType.ValueString()+" "+name+": "+err.Error(),
+		)
+		return
+	}
+	plan.ID = types.StringValue(plan.PrincipalType.ValueString() + "/" + name)
+
+	// The principal exists from here on, so failures below still record it in state and the
+	// next apply finishes the job.
+	if err := r.putPolicy(ctx, plan); err != nil {
+		resp.Diagnostics.AddError(
+			"Error attaching bucket access policy",
+			"Could not put the inline policy of "+plan.ID.ValueString()+": "+err.Error(),
+		)
+		plan.Policy = types.StringNull()
+	}
+
+	plan.AccessKeyID = types.StringNull()
+	plan.SecretAccessKey = types.StringNull()
+	if plan.CreateAccessKey.ValueBool() && !resp.Diagnostics.HasError() {
+		resp.Diagnostics.Append(r.createAccessKey(ctx, &plan)...)
+	}
+	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *s3BucketAccessResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state s3BucketAccessResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := state.Name.ValueString()
+	svc := r.client.IAMClient
+	var (
+		tags     []iamtypes.Tag
+		document *string
+		exists   bool
+		err      error
+	)
+	if state.PrincipalType.ValueString() == iamPrincipalRole {
+		var out *iam.GetRoleOutput
+		out, err = svc.GetRole(ctx, &iam.GetRoleInput{RoleName: aws.String(name)})
+		if err == nil {
+			exists = true
+			state.ARN = types.StringPointerValue(out.Role.Arn)
+			state.UniqueID = types.StringPointerValue(out.Role.RoleId)
+			state.Path = types.StringPointerValue(out.Role.Path)
+			tags = out.Role.Tags
+			trust, decodeErr := url.QueryUnescape(aws.ToString(out.Role.AssumeRolePolicyDocument))
+			if decodeErr == nil && !equivalentPolicyJSON(state.AssumeRolePolicy.ValueString(), trust) {
+				state.AssumeRolePolicy = types.StringValue(trust)
+			}
+			var policy *iam.GetRolePolicyOutput
+			policy, err = svc.GetRolePolicy(ctx, &iam.GetRolePolicyInput{RoleName: aws.String(name), PolicyName: aws.String(state.PolicyName.ValueString())})
+			if err == nil {
+				document = policy.PolicyDocument
+			}
+		}
+	} else {
+		var out *iam.GetUserOutput
+		out, err = svc.GetUser(ctx, &iam.GetUserInput{UserName: aws.String(name)})
+		if err == nil {
+			exists = true
+			state.ARN = types.StringPointerValue(out.User.Arn)
+			state.UniqueID = types.StringPointerValue(out.User.UserId)
+			state.Path = types.StringPointerValue(out.User.Path)
+			tags = out.User.Tags
+			var policy *iam.GetUserPolicyOutput
+			policy, err = svc.GetUserPolicy(ctx, &iam.GetUserPolicyInput{UserName: aws.String(name), PolicyName: aws.String(state.PolicyName.ValueString())})
+			if err == nil {
+				document = policy.PolicyDocument
+			}
+		}
+		if err == nil {
+			err = r.refreshAccessKey(ctx, &state)
+		}
+	}
+	switch {
+	case !exists && isS3ErrorCode(err, "NoSuchEntity"):
+		resp.State.RemoveResource(ctx)
+		return
+	case isS3ErrorCode(err, "NoSuchEntity"):
+		// The principal exists but the inline policy is gone; the next plan puts it back.
+		state.Policy = types.StringNull()
+	case err != nil:
+		resp.Diagnostics.AddError(
+			"Error reading IAM "+state.PrincipalType.ValueString(),
+			"Could not read "+state.ID.ValueString()+": "+err.Error(),
+		)
+		return
+	default:
+		current, decodeErr := url.QueryUnescape(aws.ToString(document))
+		if decodeErr == nil && !equivalentPolicyJSON(state.Policy.ValueString(), current) {
+			state.Policy = types.StringValue(current)
+		}
+	}
+
+	if len(tags) > 0 || !state.Tags.IsNull() {
+		tagMap, diags := types.MapValueFrom(ctx, types.StringType, iamTagsToMap(tags))
+		resp.Diagnostics.Append(diags...)
+		state.Tags = tagMap
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *s3BucketAccessResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state s3BucketAccessResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := plan.Name.ValueString()
+	svc := r.client.IAMClient
+	isRole := plan.PrincipalType.ValueString() == iamPrincipalRole
+	fail := func(action string, err error) {
+		resp.Diagnostics.AddError(
+			"Error updating IAM "+plan.PrincipalType.ValueString(),
+			"Could not "+action+" of "+plan.ID.ValueString()+": "+err.Error(),
+		)
+	}
+
+	if isRole && !equivalentPolicyJSON(plan.AssumeRolePolicy.ValueString(), state.AssumeRolePolicy.ValueString()) {
+		_, err := svc.UpdateAssumeRolePolicy(ctx, &iam.UpdateAssumeRolePolicyInput{
+			RoleName:       aws.String(name),
+			PolicyDocument: aws.String(plan.AssumeRolePolicy.ValueString()),
+		})
+		if err != nil {
+			fail("update the trust policy", err)
+			return
+		}
+	}
+
+	if !plan.Policy.Equal(state.Policy) || !plan.PolicyName.Equal(state.PolicyName) {
+		if err := r.putPolicy(ctx, plan); err != nil {
+			fail("put the inline policy", err)
+			return
+		}
+		if !plan.PolicyName.Equal(state.PolicyName) {
+			if err := r.deletePolicy(ctx, plan, state.PolicyName.ValueString()); err != nil && !isS3ErrorCode(err, "NoSuchEntity") {
+				fail("remove the previous inline policy", err)
+				return
+			}
+		}
+	}
+
+	if !plan.Tags.Equal(state.Tags) {
+		if err := r.updateTags(ctx, plan, state); err != nil {
+			fail("update the tags", err)
+			return
+		}
+	}
+
+	switch {
+	case plan.CreateAccessKey.ValueBool() && plan.AccessKeyID.IsUnknown():
+		resp.Diagnostics.Append(r.createAccessKey(ctx, &plan)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	case !plan.CreateAccessKey.ValueBool() && !state.AccessKeyID.IsNull():
+		_, err := svc.DeleteAccessKey(ctx, &iam.DeleteAccessKeyInput{
+			UserName:    aws.String(name),
+			AccessKeyId: aws.String(state.AccessKeyID.ValueString()),
+		})
+		if err != nil && !isS3ErrorCode(err, "NoSuchEntity") {
+			fail("delete the access key", err)
+			return
+		}
+	}
+	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
+
+	diags := resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete removes the principal together with everything attached to it, since IAM refuses
+// to delete roles and users that still have policies, keys or memberships.
+func (r *s3BucketAccessResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state s3BucketAccessResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var err error
+	if state.PrincipalType.ValueString() == iamPrincipalRole {
+		err = deleteIAMRole(ctx, r.client.IAMClient, state.Name.ValueString())
+	} else {
+		err = deleteIAMUser(ctx, r.client.IAMClient, state.Name.ValueString())
+	}
+	if err != nil && !isS3ErrorCode(err, "NoSuchEntity") {
+		resp.Diagnostics.AddError(
+			"Error deleting IAM "+state.PrincipalType.ValueString(),
+			"Could not delete "+state.ID.ValueString()+": "+err.Error(),
+		)
+	}
+}
+
+// ImportState imports an existing role or user by role/name or user/name. The bucket
+// settings cannot be derived from the policy, so the first plan after import rewrites it.
+func (r *s3BucketAccessResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	principalType, name, found := strings.Cut(req.ID, "/")
+	if !found || name == "" || (principalType != iamPrincipalRole && principalType != iamPrincipalUser) {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected role/name or user/name, got: %q", req.ID),
+		)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("principal_type"), principalType)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("policy_name"), s3BucketAccessDefaultPolicyName)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("require_tls"), false)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("create_access_key"), false)...)
+}
+
+// s3BucketAccessPolicyRequest collects the policy settings of the model. known is false when
+// any of them is not known yet.
+func s3BucketAccessPolicyRequest(ctx context.Context, model s3BucketAccessResourceModel) (s3AccessPolicyRequest, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	request := s3AccessPolicyRequest{
+		AccessLevel: model.AccessLevel.ValueString(),
+		RequireTLS:  model.RequireTLS.ValueBool(),
+	}
+	lists := map[*[]string]types.List{
+		&request.Buckets:        model.Buckets,
+		&request.Prefixes:       model.Prefixes,
+		&request.VPCEndpointIDs: model.VPCEndpointIDs,
+		&request.KMSKeyARNs:     model.KMSKeyARNs,
+	}
+	known := !model.AccessLevel.IsUnknown() && !model.RequireTLS.IsUnknown()
+	for target, list := range lists {
+		if list.IsUnknown() {
+			known = false
+			continue
+		}
+		for _, element := range list.Elements() {
+			if element.IsUnknown() {
+				known = false
+			}
+		}
+		if known && !list.IsNull() {
+			diags.Append(list.ElementsAs(ctx, target, false)...)
+		}
+	}
+	return request, known, diags
+}
+
+func (r *s3BucketAccessResource) putPolicy(ctx context.Context, model s3BucketAccessResourceModel) error {
+	if model.PrincipalType.ValueString() == iamPrincipalRole {
+		_, err := r.client.IAMClient.PutRolePolicy(ctx, &iam.PutRolePolicyInput{
+			RoleName:       aws.String(model.Name.ValueString()),
+			PolicyName:     aws.String(model.PolicyName.ValueString()),
+			PolicyDocument: aws.String(model.Policy.ValueString()),
+		})
+		return err
+	}
+	_, err := r.client.IAMClient.PutUserPolicy(ctx, &iam.PutUserPolicyInput{
+		UserName:       aws.String(model.Name.ValueString()),
+		PolicyName:     aws.String(model.PolicyName.ValueString()),
+		PolicyDocument: aws.String(model.Policy.ValueString()),
+	})
+	return err
+}
+
+func (r *s3BucketAccessResource) deletePolicy(ctx context.Context, model s3BucketAccessResourceModel, policyName string) error {
+	if model.PrincipalType.ValueString() == iamPrincipalRole {
+		_, err := r.client.IAMClient.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{
+			RoleName:   aws.String(model.Name.ValueString()),
+			PolicyName: aws.String(policyName),
+		})
+		return err
+	}
+	_, err := r.client.IAMClient.DeleteUserPolicy(ctx, &iam.DeleteUserPolicyInput{
+		UserName:   aws.String(model.Name.ValueString()),
+		PolicyName: aws.String(policyName),
+	})
+	return err
+}
+
+// updateTags applies the planned tags, removing keys that are no longer configured.
+func (r *s3BucketAccessResource) updateTags(ctx context.Context, plan, state s3BucketAccessResourceModel) error {
+	name := aws.String(plan.Name.ValueString())
+	svc := r.client.IAMClient
+	if plan.PrincipalType.ValueString() == iamPrincipalRole {
+		return updateAWSTags(ctx, plan.Tags, state.Tags,
+			func(keys []string) error {
+				_, err := svc.UntagRole(ctx, &iam.UntagRoleInput{RoleName: name, TagKeys: keys})
+				return err
+			},
+			func(tags map[string]string) error {
+				_, err := svc.TagRole(ctx, &iam.TagRoleInput{RoleName: name, Tags: iamTags(tags)})
+				return err
+			},
+		)
+	}
+	return updateAWSTags(ctx, plan.Tags, state.Tags,
+		func(keys []string) error {
+			_, err := svc.UntagUser(ctx, &iam.UntagUserInput{UserName: name, TagKeys: keys})
+			return err
+		},
+		func(tags map[string]string) error {
+			_, err := svc.TagUser(ctx, &iam.TagUserInput{UserName: name, Tags: iamTags(tags)})
+			return err
+		},
+	)
+}
+
+// createAccessKey issues an access key for the user and records it in model.
+func (r *s3BucketAccessResource) createAccessKey(ctx context.Context, model *s3BucketAccessResourceModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+	out, err := r.client.IAMClient.CreateAccessKey(ctx, &iam.CreateAccessKeyInput{
+		UserName: aws.String(model.Name.ValueString()),
+	})
+	if err != nil {
+		diags.AddError(
+			"Error creating access key",
+			"Could not create an access key for "+model.ID.ValueString()+": "+err.Error(),
+		)
+		model.AccessKeyID = types.StringNull()
+		model.SecretAccessKey = types.StringNull()
+		return diags
+	}
+	model.AccessKeyID = types.StringPointerValue(out.AccessKey.AccessKeyId)
+	model.SecretAccessKey = types.StringPointerValue(out.AccessKey.SecretAccessKey)
+	return diags
+}
+
+// refreshAccessKey forgets the access key in state if it was deleted outside Terraform, so
+// the next plan issues a new one.
+func (r *s3BucketAccessResource) refreshAccessKey(ctx context.Context, model *s3BucketAccessResourceModel) error {
+	if model.AccessKeyID.IsNull() {
+		return nil
+	}
+	paginator := iam.NewListAccessKeysPaginator(r.client.IAMClient, &iam.ListAccessKeysInput{
+		UserName: aws.String(model.Name.ValueString()),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, key := range page.AccessKeyMetadata {
+			if aws.ToString(key.AccessKeyId) == model.AccessKeyID.ValueString() {
+				return nil
+			}
+		}
+	}
+	model.AccessKeyID = types.StringNull()
+	model.SecretAccessKey = types.StringNull()
+	return nil
+}
+
+// deleteIAMRole detaches everything from a role and deletes it.
+func deleteIAMRole(ctx context.Context, svc *iam.Client, name string) error {
+	role := aws.String(name)
+
+	inline := iam.NewListRolePoliciesPaginator(svc, &iam.ListRolePoliciesInput{RoleName: role})
+	for inline.HasMorePages() {
+		page, err := inline.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, policyName := range page.PolicyNames {
+			if _, err := svc.DeleteRolePolicy(ctx, &iam.DeleteRolePolicyInput{RoleName: role, PolicyName: aws.String(policyName)}); err != nil {
+				return err
+			}
+		}
+	}
+
+	attached := iam.NewListAttachedRolePoliciesPaginator(svc, &iam.ListAttachedRolePoliciesInput{RoleName: role})
+	for attached.HasMorePages() {
+		page, err := attached.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, policy := range page.AttachedPolicies {
+			if _, err := svc.DetachRolePolicy(ctx, &iam.DetachRolePolicyInput{RoleName: role, PolicyArn: policy.PolicyArn}); err != nil {
+				return err
+			}
+		}
+	}
+
+	profiles := iam.NewListInstanceProfilesForRolePaginator(svc, &iam.ListInstanceProfilesForRoleInput{RoleName: role})
+	for profiles.HasMorePages() {
+		page, err := profiles.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, profile := range page.InstanceProfiles {
+			_, err := svc.RemoveRoleFromInstanceProfile(ctx, &iam.RemoveRoleFromInstanceProfileInput{
+				RoleName:            role,
+				InstanceProfileName: profile.InstanceProfileName,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := svc.DeleteRole(ctx, &iam.DeleteRoleInput{RoleName: role})
+	return err
+}
+
+// deleteIAMUser removes the access keys, signing certificates, SSH keys, service-specific
+// credentials, policies, group memberships, console password and MFA devices of a user and
+// deletes it.
+func deleteIAMUser(ctx context.Context, svc *iam.Client, name string) error {
+	user := aws.String(name)
+
+	keys := iam.NewListAccessKeysPaginator(svc, &iam.ListAccessKeysInput{UserName: user})
+	for keys.HasMorePages() {
+		page, err := keys.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, key := range page.AccessKeyMetadata {
+			if _, err := svc.DeleteAccessKey(ctx, &iam.DeleteAccessKeyInput{UserName: user, AccessKeyId: key.AccessKeyId}); err != nil {
+				return err
+			}
+		}
+	}
+
+	certificates := iam.NewListSigningCertificatesPaginator(svc, &iam.ListSigningCertificatesInput{UserName: user})
+	for certificates.HasMorePages() {
+		page, err := certificates.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, certificate := range page.Certificates {
+			if _, err := svc.DeleteSigningCertificate(ctx, &iam.DeleteSigningCertificateInput{UserName: user, CertificateId: certificate.CertificateId}); err != nil {
+				return err
+			}
+		}
+	}
+
+	sshKeys := iam.NewListSSHPublicKeysPaginator(svc, &iam.ListSSHPublicKeysInput{UserName: user})
+	for sshKeys.HasMorePages() {
+		page, err := sshKeys.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, key := range page.SSHPublicKeys {
+			if _, err := svc.DeleteSSHPublicKey(ctx, &iam.DeleteSSHPublicKeyInput{UserName: user, SSHPublicKeyId: key.SSHPublicKeyId}); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Service-specific credentials are not paginated.
+	credentials, err := svc.ListServiceSpecificCredentials(ctx, &iam.ListServiceSpecificCredentialsInput{UserName: user})
+	if err != nil {
+		return err
+	}
+	for _, credential := range credentials.ServiceSpecificCredentials {
+		if _, err := svc.DeleteServiceSpecificCredential(ctx, &iam.DeleteServiceSpecificCredentialInput{UserName: user, ServiceSpecificCredentialId: credential.ServiceSpecificCredentialId}); err != nil {
+			return err
+		}
+	}
+
+	inline := iam.NewListUserPoliciesPaginator(svc, &iam.ListUserPoliciesInput{UserName: user})
+	for inline.HasMorePages() {
+		page, err := inline.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, policyName := range page.PolicyNames {
+			if _, err := svc.DeleteUserPolicy(ctx, &iam.DeleteUserPolicyInput{UserName: user, PolicyName: aws.String(policyName)}); err != nil {
+				return err
+			}
+		}
+	}
+
+	attached := iam.NewListAttachedUserPoliciesPaginator(svc, &iam.ListAttachedUserPoliciesInput{UserName: user})
+	for attached.HasMorePages() {
+		page, err := attached.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, policy := range page.AttachedPolicies {
+			if _, err := svc.DetachUserPolicy(ctx, &iam.DetachUserPolicyInput{UserName: user, PolicyArn: policy.PolicyArn}); err != nil {
+				return err
+			}
+		}
+	}
+
+	groups := iam.NewListGroupsForUserPaginator(svc, &iam.ListGroupsForUserInput{UserName: user})
+	for groups.HasMorePages() {
+		page, err := groups.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, group := range page.Groups {
+			if _, err := svc.RemoveUserFromGroup(ctx, &iam.RemoveUserFromGroupInput{UserName: user, GroupName: group.GroupName}); err != nil {
+				return err
+			}
+		}
+	}
+
+	devices := iam.NewListMFADevicesPaginator(svc, &iam.ListMFADevicesInput{UserName: user})
+	for devices.HasMorePages() {
+		page, err := devices.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, device := range page.MFADevices {
+			if _, err := svc.DeactivateMFADevice(ctx, &iam.DeactivateMFADeviceInput{UserName: user, SerialNumber: device.SerialNumber}); err != nil {
+				return err
+			}
+		}
+	}
+
+	if _, err := svc.DeleteLoginProfile(ctx, &iam.DeleteLoginProfileInput{UserName: user}); err != nil && !isS3ErrorCode(err, "NoSuchEntity") {
+		return err
+	}
+
+	_, err = svc.DeleteUser(ctx, &iam.DeleteUserInput{UserName: user})
+	return err
+}
+
+func iamTagsFromMap(ctx context.Context, tags types.Map) ([]iamtypes.Tag, diag.Diagnostics) {
+	values := map[string]string{}
+	diags := tags.ElementsAs(ctx, &values, false)
+	return iamTags(values), diags
+}
+
+func iamTags(tags map[string]string) []iamtypes.Tag {
+	var tagSet []iamtypes.Tag
+	for key, value := range tags {
+		tagSet = append(tagSet, iamtypes.Tag{Key: aws.String(key), Value: aws.String(value)})
+	}
+	return tagSet
+}
+
+func iamTagsToMap(tagSet []iamtypes.Tag) map[string]string {
+	tags := map[string]string{}
+	for _, tag := range tagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags
+}
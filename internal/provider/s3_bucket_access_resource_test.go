@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestS3BucketAccessPolicyRequest(t *testing.T) {
+	ctx := context.Background()
+	model := s3BucketAccessResourceModel{
+		Buckets:        types.ListValueMust(types.StringType, []attr.Value{types.StringValue("data")}),
+		Prefixes:       types.ListNull(types.StringType),
+		VPCEndpointIDs: types.ListNull(types.StringType),
+		KMSKeyARNs:     types.ListNull(types.StringType),
+		AccessLevel:    types.StringValue(s3AccessRead),
+		RequireTLS:     types.BoolValue(true),
+	}
+
+	request, known, diags := s3BucketAccessPolicyRequest(ctx, model)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if !known || len(request.Buckets) != 1 || request.Buckets[0] != "data" || !request.RequireTLS {
+		t.Errorf("request = %+v, known = %v", request, known)
+	}
+
+	model.Buckets = types.ListValueMust(types.StringType, []attr.Value{types.StringUnknown()})
+	if _, known, _ := s3BucketAccessPolicyRequest(ctx, model); known {
+		t.Error("request with an unknown bucket reported as known")
+	}
+}
@@ -0,0 +1,427 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &s3BucketDataSource{}
+	_ datasource.DataSourceWithConfigure = &s3BucketDataSource{}
+)
+
+// NewS3BucketDataSource is a helper function to simplify the provider implementation.
+func NewS3BucketDataSource() datasource.DataSource {
+	return &s3BucketDataSource{}
+}
+
+// s3BucketDataSource reads the configuration of one bucket without managing it.
+type s3BucketDataSource struct {
+	client *ClientS3
+}
+
+type s3BucketDataSourceModel struct {
+	Bucket                   types.String                 `tfsdk:"bucket"`
+	Region                   types.String                 `tfsdk:"region"`
+	ARN                      types.String                 `tfsdk:"arn"`
+	CreationDate             types.String                 `tfsdk:"creation_date"`
+	BucketDomainName         types.String                 `tfsdk:"bucket_domain_name"`
+	BucketRegionalDomainName types.String                 `tfsdk:"bucket_regional_domain_name"`
+	VersioningStatus         types.String                 `tfsdk:"versioning_status"`
+	MFADelete                types.String                 `tfsdk:"mfa_delete"`
+	Encryption               []s3BucketEncryptionModel    `tfsdk:"encryption"`
+	PublicAccessBlock        *s3PublicAccessBlockModel    `tfsdk:"public_access_block"`
+	ObjectOwnership          types.String                 `tfsdk:"object_ownership"`
+	Policy                   types.String                 `tfsdk:"policy"`
+	LifecycleRules           []s3BucketLifecycleRuleModel `tfsdk:"lifecycle_rules"`
+	Tags                     map[string]string            `tfsdk:"tags"`
+}
+
+type s3BucketEncryptionModel struct {
+	SSEAlgorithm     types.String `tfsdk:"sse_algorithm"`
+	KMSMasterKeyID   types.String `tfsdk:"kms_master_key_id"`
+	BucketKeyEnabled types.Bool   `tfsdk:"bucket_key_enabled"`
+}
+
+type s3PublicAccessBlockModel struct {
+	BlockPublicAcls       types.Bool `tfsdk:"block_public_acls"`
+	BlockPublicPolicy     types.Bool `tfsdk:"block_public_policy"`
+	IgnorePublicAcls      types.Bool `tfsdk:"ignore_public_acls"`
+	RestrictPublicBuckets types.Bool `tfsdk:"restrict_public_buckets"`
+}
+
+type s3BucketLifecycleRuleModel struct {
+	ID                                 types.String                 `tfsdk:"id"`
+	Status                             types.String                 `tfsdk:"status"`
+	Prefix                             types.String                 `tfsdk:"prefix"`
+	Tags                               map[string]string            `tfsdk:"tags"`
+	ExpirationDays                     types.Int64                  `tfsdk:"expiration_days"`
+	ExpirationDate                     types.String                 `tfsdk:"expiration_date"`
+	ExpiredObjectDeleteMarker          types.Bool                   `tfsdk:"expired_object_delete_marker"`
+	NoncurrentVersionExpirationDays    types.Int64                  `tfsdk:"noncurrent_version_expiration_days"`
+	AbortIncompleteMultipartUploadDays types.Int64                  `tfsdk:"abort_incomplete_multipart_upload_days"`
+	Transitions                        []s3LifecycleTransitionModel `tfsdk:"transitions"`
+	NoncurrentVersionTransitions       []s3LifecycleTransitionModel `tfsdk:"noncurrent_version_transitions"`
+}
+
+// s3LifecycleTransitionModel is a storage class transition. For noncurrent versions days
+// counts from the moment the version became noncurrent and date is never set.
+type s3LifecycleTransitionModel struct {
+	Days         types.Int64  `tfsdk:"days"`
+	Date         types.String `tfsdk:"date"`
+	StorageClass types.String `tfsdk:"storage_class"`
+}
+
+// Metadata returns the data source type name.
+func (d *s3BucketDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_bucket"
+}
+
+// Schema defines the schema for the data source.
+func (d *s3BucketDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	computedString := func(description string) schema.StringAttribute {
+		return schema.StringAttribute{Computed: true, Description: description}
+	}
+	computedBool := schema.BoolAttribute{Computed: true}
+	computedInt64 := schema.Int64Attribute{Computed: true}
+	transitions := func(description string) schema.ListNestedAttribute {
+		return schema.ListNestedAttribute{
+			Computed:    true,
+			Description: description,
+			NestedObject: schema.NestedAttributeObject{
+				Attributes: map[string]schema.Attribute{
+					"days":          computedInt64,
+					"date":          computedString("RFC 3339 date of the transition."),
+					"storage_class": computedString(""),
+				},
+			},
+		}
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Looks up one S3 bucket by name and returns its configuration.",
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required: true,
+			},
+			"region":                      computedString(""),
+			"arn":                         computedString(""),
+			"creation_date":               computedString("RFC 3339 creation time. Only known for buckets of the caller's account."),
+			"bucket_domain_name":          computedString("Global virtual-hosted-style host name."),
+			"bucket_regional_domain_name": computedString("Regional virtual-hosted-style host name."),
+			"versioning_status":           computedString("Enabled, Suspended, or empty if versioning was never enabled."),
+			"mfa_delete":                  computedString("Enabled or Disabled, empty if never configured."),
+			"encryption": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Default encryption rules.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"sse_algorithm":      computedString(""),
+						"kms_master_key_id":  computedString(""),
+						"bucket_key_enabled": computedBool,
+					},
+				},
+			},
+			"public_access_block": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "Bucket-level public access block, null if not configured.",
+				Attributes: map[string]schema.Attribute{
+					"block_public_acls":       computedBool,
+					"block_public_policy":     computedBool,
+					"ignore_public_acls":      computedBool,
+					"restrict_public_buckets": computedBool,
+				},
+			},
+			"object_ownership": computedString("Object Ownership setting, null if not configured."),
+			"policy":           computedString("Bucket policy JSON, null if the bucket has none."),
+			"lifecycle_rules": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id":     computedString(""),
+						"status": computedString(""),
+						"prefix": computedString("Key prefix the rule applies to."),
+						"tags": schema.MapAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+							Description: "Tags an object must carry for the rule to apply.",
+						},
+						"expiration_days":                        computedInt64,
+						"expiration_date":                        computedString("RFC 3339 date objects expire on."),
+						"expired_object_delete_marker":           computedBool,
+						"noncurrent_version_expiration_days":     computedInt64,
+						"abort_incomplete_multipart_upload_days": computedInt64,
+						"transitions":                            transitions("Storage class transitions of current versions."),
+						"noncurrent_version_transitions":         transitions("Storage class transitions of noncurrent versions."),
+					},
+				},
+			},
+			"tags": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *s3BucketDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state s3BucketDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	svc := d.client.S3Client
+	bucket := state.Bucket.ValueString()
+	addError := func(what string, err error) {
+		resp.Diagnostics.AddError(
+			"unable to read bucket "+what,
+			"Could not read the "+what+" of bucket "+bucket+": "+err.Error(),
+		)
+	}
+
+	region, err := readS3BucketRegion(ctx, svc, bucket)
+	if err != nil {
+		addError("region", err)
+		return
+	}
+	// Every other call goes to the bucket's own region.
+	inRegion := func(o *s3.Options) { o.Region = region }
+
+	state.Region = types.StringValue(region)
+	state.ARN = types.StringValue(s3BucketARN(bucket, region))
+	dnsSuffix := awsPartitionDNSSuffix(awsPartition(region))
+	state.BucketDomainName = types.StringValue(bucket + ".s3." + dnsSuffix)
+	state.BucketRegionalDomainName = types.StringValue(bucket + ".s3." + region + "." + dnsSuffix)
+
+	state.CreationDate = types.StringNull()
+	listed, err := svc.ListBuckets(ctx, &s3.ListBucketsInput{Prefix: aws.String(bucket), BucketRegion: aws.String(region)})
+	if err != nil {
+		resp.Diagnostics.AddAttributeWarning(
+			path.Root("creation_date"),
+			"Bucket Creation Date Not Read",
+			"Could not list buckets to find the creation date of bucket "+bucket+", so creation_date is left empty: "+err.Error(),
+		)
+	} else {
+		for _, item := range listed.Buckets {
+			if aws.ToString(item.Name) == bucket {
+				state.CreationDate = types.StringValue(aws.ToTime(item.CreationDate).Format(time.RFC3339))
+			}
+		}
+	}
+
+	versioning, err := svc.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{Bucket: aws.String(bucket)}, inRegion)
+	if err != nil {
+		addError("versioning", err)
+		return
+	}
+	state.VersioningStatus = types.StringValue(string(versioning.Status))
+	state.MFADelete = types.StringValue(string(versioning.MFADelete))
+
+	state.Encryption = []s3BucketEncryptionModel{}
+	encryption, err := svc.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{Bucket: aws.String(bucket)}, inRegion)
+	switch {
+	case isS3ErrorCode(err, "ServerSideEncryptionConfigurationNotFoundError"):
+	case err != nil:
+		addError("encryption", err)
+		return
+	case encryption.ServerSideEncryptionConfiguration != nil:
+		for _, rule := range encryption.ServerSideEncryptionConfiguration.Rules {
+			item := s3BucketEncryptionModel{
+				SSEAlgorithm:     types.StringNull(),
+				KMSMasterKeyID:   types.StringNull(),
+				BucketKeyEnabled: types.BoolValue(aws.ToBool(rule.BucketKeyEnabled)),
+			}
+			if byDefault := rule.ApplyServerSideEncryptionByDefault; byDefault != nil {
+				item.SSEAlgorithm = types.StringValue(string(byDefault.SSEAlgorithm))
+				item.KMSMasterKeyID = types.StringPointerValue(byDefault.KMSMasterKeyID)
+			}
+			state.Encryption = append(state.Encryption, item)
+		}
+	}
+
+	publicAccess, err := svc.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{Bucket: aws.String(bucket)}, inRegion)
+	switch {
+	case isS3ErrorCode(err, "NoSuchPublicAccessBlockConfiguration"):
+	case err != nil:
+		addError("public access block", err)
+		return
+	case publicAccess.PublicAccessBlockConfiguration != nil:
+		block := publicAccess.PublicAccessBlockConfiguration
+		state.PublicAccessBlock = &s3PublicAccessBlockModel{
+			BlockPublicAcls:       types.BoolValue(aws.ToBool(block.BlockPublicAcls)),
+			BlockPublicPolicy:     types.BoolValue(aws.ToBool(block.BlockPublicPolicy)),
+			IgnorePublicAcls:      types.BoolValue(aws.ToBool(block.IgnorePublicAcls)),
+			RestrictPublicBuckets: types.BoolValue(aws.ToBool(block.RestrictPublicBuckets)),
+		}
+	}
+
+	state.ObjectOwnership = types.StringNull()
+	ownership, err := svc.GetBucketOwnershipControls(ctx, &s3.GetBucketOwnershipControlsInput{Bucket: aws.String(bucket)}, inRegion)
+	switch {
+	case isS3ErrorCode(err, "OwnershipControlsNotFoundError"):
+	case err != nil:
+		addError("ownership controls", err)
+		return
+	case ownership.OwnershipControls != nil && len(ownership.OwnershipControls.Rules) > 0:
+		state.ObjectOwnership = types.StringValue(string(ownership.OwnershipControls.Rules[0].ObjectOwnership))
+	}
+
+	state.Policy = types.StringNull()
+	policy, err := svc.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{Bucket: aws.String(bucket)}, inRegion)
+	switch {
+	case isS3ErrorCode(err, "NoSuchBucketPolicy"):
+	case err != nil:
+		addError("policy", err)
+		return
+	default:
+		state.Policy = types.StringPointerValue(policy.Policy)
+	}
+
+	state.LifecycleRules = []s3BucketLifecycleRuleModel{}
+	lifecycle, err := svc.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{Bucket: aws.String(bucket)}, inRegion)
+	switch {
+	case isS3ErrorCode(err, "NoSuchLifecycleConfiguration"):
+	case err != nil:
+		addError("lifecycle rules", err)
+		return
+	default:
+		for _, rule := range lifecycle.Rules {
+			state.LifecycleRules = append(state.LifecycleRules, flattenS3LifecycleRule(rule))
+		}
+	}
+
+	tags, err := readS3BucketTags(ctx, svc, bucket, region)
+	if err != nil {
+		addError("tags", err)
+		return
+	}
+	state.Tags = tags
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// readS3BucketRegion returns the region a bucket lives in. GetBucketLocation answers from
+// any region; callers without permission for it fall back to HeadBucket.
+func readS3BucketRegion(ctx context.Context, svc *s3.Client, bucket string) (string, error) {
+	location, err := svc.GetBucketLocation(ctx, &s3.GetBucketLocationInput{Bucket: aws.String(bucket)})
+	if err == nil {
+		switch location.LocationConstraint {
+		case "":
+			return "us-east-1", nil
+		case awstypes.BucketLocationConstraintEu:
+			return "eu-west-1", nil
+		default:
+			return string(location.LocationConstraint), nil
+		}
+	}
+	if !isS3ErrorCode(err, "AccessDenied") {
+		return "", err
+	}
+
+	head, err := svc.HeadBucket(ctx, &s3.HeadBucketInput{Bucket: aws.String(bucket)})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(head.BucketRegion), nil
+}
+
+func flattenS3LifecycleRule(rule awstypes.LifecycleRule) s3BucketLifecycleRuleModel {
+	item := s3BucketLifecycleRuleModel{
+		ID:                                 types.StringPointerValue(rule.ID),
+		Status:                             types.StringValue(string(rule.Status)),
+		Prefix:                             types.StringPointerValue(rule.Prefix),
+		ExpirationDays:                     types.Int64Null(),
+		ExpirationDate:                     types.StringNull(),
+		ExpiredObjectDeleteMarker:          types.BoolNull(),
+		NoncurrentVersionExpirationDays:    types.Int64Null(),
+		AbortIncompleteMultipartUploadDays: types.Int64Null(),
+		Transitions:                        []s3LifecycleTransitionModel{},
+		NoncurrentVersionTransitions:       []s3LifecycleTransitionModel{},
+	}
+	if filter := rule.Filter; filter != nil {
+		switch {
+		case filter.And != nil:
+			item.Prefix = types.StringPointerValue(filter.And.Prefix)
+			item.Tags = s3TagsToMap(filter.And.Tags)
+		case filter.Tag != nil:
+			item.Tags = s3TagsToMap([]awstypes.Tag{*filter.Tag})
+		case filter.Prefix != nil:
+			item.Prefix = types.StringPointerValue(filter.Prefix)
+		}
+	}
+	if expiration := rule.Expiration; expiration != nil {
+		item.ExpirationDays = optionalInt64(expiration.Days)
+		item.ExpirationDate = optionalDate(expiration.Date)
+		if expiration.ExpiredObjectDeleteMarker != nil {
+			item.ExpiredObjectDeleteMarker = types.BoolValue(*expiration.ExpiredObjectDeleteMarker)
+		}
+	}
+	if rule.NoncurrentVersionExpiration != nil {
+		item.NoncurrentVersionExpirationDays = optionalInt64(rule.NoncurrentVersionExpiration.NoncurrentDays)
+	}
+	if rule.AbortIncompleteMultipartUpload != nil {
+		item.AbortIncompleteMultipartUploadDays = optionalInt64(rule.AbortIncompleteMultipartUpload.DaysAfterInitiation)
+	}
+	for _, transition := range rule.Transitions {
+		item.Transitions = append(item.Transitions, s3LifecycleTransitionModel{
+			Days:         optionalInt64(transition.Days),
+			Date:         optionalDate(transition.Date),
+			StorageClass: types.StringValue(string(transition.StorageClass)),
+		})
+	}
+	for _, transition := range rule.NoncurrentVersionTransitions {
+		item.NoncurrentVersionTransitions = append(item.NoncurrentVersionTransitions, s3LifecycleTransitionModel{
+			Days:         optionalInt64(transition.NoncurrentDays),
+			Date:         types.StringNull(),
+			StorageClass: types.StringValue(string(transition.StorageClass)),
+		})
+	}
+	return item
+}
+
+func optionalInt64(value *int32) types.Int64 {
+	if value == nil {
+		return types.Int64Null()
+	}
+	return types.Int64Value(int64(*value))
+}
+
+func optionalDate(value *time.Time) types.String {
+	if value == nil {
+		return types.StringNull()
+	}
+	return types.StringValue(value.UTC().Format(time.RFC3339))
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *s3BucketDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestFlattenS3LifecycleRule(t *testing.T) {
+	rule := flattenS3LifecycleRule(awstypes.LifecycleRule{
+		ID:     aws.String("archive"),
+		Status: awstypes.ExpirationStatusEnabled,
+		Filter: &awstypes.LifecycleRuleFilter{
+			And: &awstypes.LifecycleRuleAndOperator{
+				Prefix: aws.String("logs/"),
+				Tags:   []awstypes.Tag{{Key: aws.String("tier"), Value: aws.String("cold")}},
+			},
+		},
+		Expiration:  &awstypes.LifecycleExpiration{Days: aws.Int32(365)},
+		Transitions: []awstypes.Transition{{Days: aws.Int32(30), StorageClass: awstypes.TransitionStorageClassGlacier}},
+	})
+
+	if rule.Prefix.ValueString() != "logs/" || rule.Tags["tier"] != "cold" {
+		t.Errorf("filter = %q %v, want logs/ with tier=cold", rule.Prefix.ValueString(), rule.Tags)
+	}
+	if rule.ExpirationDays.ValueInt64() != 365 || !rule.ExpirationDate.IsNull() {
+		t.Errorf("expiration = %v %v, want 365 days and no date", rule.ExpirationDays, rule.ExpirationDate)
+	}
+	if !rule.NoncurrentVersionExpirationDays.IsNull() {
+		t.Error("noncurrent expiration set without a rule")
+	}
+	if len(rule.Transitions) != 1 || rule.Transitions[0].StorageClass.ValueString() != "GLACIER" {
+		t.Errorf("transitions = %v, want one GLACIER transition", rule.Transitions)
+	}
+}
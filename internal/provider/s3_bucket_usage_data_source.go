@@ -0,0 +1,329 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &s3BucketUsageDataSource{}
+	_ datasource.DataSourceWithConfigure = &s3BucketUsageDataSource{}
+)
+
+const (
+	s3UsageDefaultConcurrency = 16
+	s3UsageDefaultMaxScan     = 300
+	// s3UsageMaxDiscoveryDepth bounds how many delimiter levels are walked looking for
+	// enough prefixes to keep every worker busy.
+	s3UsageMaxDiscoveryDepth = 3
+)
+
+// NewS3BucketUsageDataSource is a helper function to simplify the provider implementation.
+func NewS3BucketUsageDataSource() datasource.DataSource {
+	return &s3BucketUsageDataSource{}
+}
+
+// s3BucketUsageDataSource adds up object counts and sizes of a bucket by storage class.
+type s3BucketUsageDataSource struct {
+	client *ClientS3
+}
+
+type s3BucketUsageDataSourceModel struct {
+	Bucket          types.String                        `tfsdk:"bucket"`
+	Prefix          types.String                        `tfsdk:"prefix"`
+	Delimiter       types.String                        `tfsdk:"delimiter"`
+	Concurrency     types.Int64                         `tfsdk:"concurrency"`
+	MaxScanSeconds  types.Int64                         `tfsdk:"max_scan_seconds"`
+	ObjectCount     types.Int64                         `tfsdk:"object_count"`
+	TotalBytes      types.Int64                         `tfsdk:"total_bytes"`
+	StorageClasses  map[string]s3StorageClassUsageModel `tfsdk:"storage_classes"`
+	Complete        types.Bool                          `tfsdk:"complete"`
+	ScannedPrefixes types.Int64                         `tfsdk:"scanned_prefixes"`
+}
+
+type s3StorageClassUsageModel struct {
+	ObjectCount types.Int64 `tfsdk:"object_count"`
+	TotalBytes  types.Int64 `tfsdk:"total_bytes"`
+}
+
+// s3UsageTally collects per storage class totals from concurrent listings.
+type s3UsageTally struct {
+	mu      sync.Mutex
+	classes map[string]*s3StorageClassUsage
+}
+
+type s3StorageClassUsage struct {
+	objects int64
+	bytes   int64
+}
+
+// add counts a page of listed objects. Objects listed without a storage class are STANDARD.
+func (t *s3UsageTally) add(objects []awstypes.Object) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.classes == nil {
+		t.classes = map[string]*s3StorageClassUsage{}
+	}
+	for _, object := range objects {
+		class := string(object.StorageClass)
+		if class == "" {
+			class = string(awstypes.ObjectStorageClassStandard)
+		}
+		usage, ok := t.classes[class]
+		if !ok {
+			usage = &s3StorageClassUsage{}
+			t.classes[class] = usage
+		}
+		usage.objects++
+		usage.bytes += aws.ToInt64(object.Size)
+	}
+}
+
+// Metadata returns the data source type name.
+func (d *s3BucketUsageDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_bucket_usage"
+}
+
+// Schema defines the schema for the data source.
+func (d *s3BucketUsageDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Counts the objects and bytes of a bucket per storage class, optionally under a prefix. " +
+			"Keys are listed in parallel across the prefixes found with the delimiter.",
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required: true,
+			},
+			"prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only count keys starting with this prefix.",
+			},
+			"delimiter": schema.StringAttribute{
+				Optional: true,
+				Description: "Delimiter used to split the key space into prefixes listed in parallel. Defaults to \"/\", " +
+					"the only delimiter directory buckets support.",
+			},
+			"concurrency": schema.Int64Attribute{
+				Optional:    true,
+				Description: fmt.Sprintf("Number of prefixes listed at once. Defaults to %d.", s3UsageDefaultConcurrency),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 64),
+				},
+			},
+			"max_scan_seconds": schema.Int64Attribute{
+				Optional: true,
+				Description: fmt.Sprintf("Stop listing after this many seconds and report what was counted so far, "+
+					"with complete set to false. Defaults to %d.", s3UsageDefaultMaxScan),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"object_count": schema.Int64Attribute{
+				Computed: true,
+			},
+			"total_bytes": schema.Int64Attribute{
+				Computed: true,
+			},
+			"storage_classes": schema.MapNestedAttribute{
+				Computed:    true,
+				Description: "Totals keyed by storage class.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"object_count": schema.Int64Attribute{
+							Computed: true,
+						},
+						"total_bytes": schema.Int64Attribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+			"complete": schema.BoolAttribute{
+				Computed:    true,
+				Description: "False when the scan hit max_scan_seconds and the totals are partial.",
+			},
+			"scanned_prefixes": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of prefixes that were listed in parallel.",
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *s3BucketUsageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state s3BucketUsageDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	delimiter := "/"
+	if !state.Delimiter.IsNull() && state.Delimiter.ValueString() != "" {
+		delimiter = state.Delimiter.ValueString()
+	}
+	if isS3DirectoryBucket(state.Bucket.ValueString()) {
+		if err := validateS3DirectoryBucketListing(state.Prefix.ValueString(), delimiter); err != nil {
+			resp.Diagnostics.AddError("Unsupported Directory Bucket Listing", err.Error())
+			return
+		}
+	}
+	concurrency := s3UsageDefaultConcurrency
+	if !state.Concurrency.IsNull() {
+		concurrency = int(state.Concurrency.ValueInt64())
+	}
+	maxScan := time.Duration(s3UsageDefaultMaxScan) * time.Second
+	if !state.MaxScanSeconds.IsNull() {
+		maxScan = time.Duration(state.MaxScanSeconds.ValueInt64()) * time.Second
+	}
+
+	scan := s3UsageScan{
+		svc:    d.client.S3Client,
+		bucket: state.Bucket.ValueString(),
+	}
+	scanned, complete, diags := scan.run(ctx, state.Prefix.ValueString(), delimiter, concurrency, maxScan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	state.Complete = types.BoolValue(complete)
+	state.ScannedPrefixes = types.Int64Value(int64(scanned))
+	var objects, bytes int64
+	state.StorageClasses = map[string]s3StorageClassUsageModel{}
+	for class, usage := range scan.tally.classes {
+		objects += usage.objects
+		bytes += usage.bytes
+		state.StorageClasses[class] = s3StorageClassUsageModel{
+			ObjectCount: types.Int64Value(usage.objects),
+			TotalBytes:  types.Int64Value(usage.bytes),
+		}
+	}
+	state.ObjectCount = types.Int64Value(objects)
+	state.TotalBytes = types.Int64Value(bytes)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// s3UsageScan lists one bucket and tallies what it finds.
+type s3UsageScan struct {
+	svc    s3.ListObjectsV2APIClient
+	bucket string
+	tally  s3UsageTally
+}
+
+// run tallies every key under prefix, listing the prefixes found by discover in parallel. It
+// returns how many prefixes were listed in parallel and whether the scan finished within
+// maxScan; a scan cut short by maxScan is reported as a warning, not an error.
+func (s *s3UsageScan) run(ctx context.Context, prefix, delimiter string, concurrency int, maxScan time.Duration) (int, bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	scanCtx, cancel := context.WithTimeout(ctx, maxScan)
+	defer cancel()
+
+	prefixes, err := s.discover(scanCtx, prefix, delimiter, concurrency)
+	if err == nil {
+		err = forEachConcurrently(scanCtx, concurrency, prefixes, func(ctx context.Context, prefix string) error {
+			_, err := s.listLevel(ctx, prefix, "")
+			return err
+		})
+	}
+	if err != nil {
+		if !errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
+			diags.AddError(
+				"unable to compute bucket usage",
+				"Could not list objects in bucket "+s.bucket+": "+err.Error(),
+			)
+			return len(prefixes), false, diags
+		}
+		diags.AddWarning(
+			"Bucket usage is incomplete",
+			fmt.Sprintf("Listing bucket %s did not finish within %s; the totals only cover the keys listed so far.", s.bucket, maxScan),
+		)
+		return len(prefixes), false, diags
+	}
+	return len(prefixes), true, diags
+}
+
+// discover walks the key space below prefix one delimiter level at a time until there are at
+// least want prefixes to list in parallel, counting the objects found directly on each level
+// on the way. It returns the prefixes still to be listed in full.
+func (s *s3UsageScan) discover(ctx context.Context, prefix, delimiter string, want int) ([]string, error) {
+	frontier := []string{prefix}
+	for depth := 0; depth < s3UsageMaxDiscoveryDepth && len(frontier) < want; depth++ {
+		var (
+			mu   sync.Mutex
+			next []string
+		)
+		err := forEachConcurrently(ctx, want, frontier, func(ctx context.Context, prefix string) error {
+			found, err := s.listLevel(ctx, prefix, delimiter)
+			mu.Lock()
+			next = append(next, found...)
+			mu.Unlock()
+			return err
+		})
+		if err != nil {
+			return next, err
+		}
+		frontier = next
+		if len(frontier) == 0 {
+			break
+		}
+	}
+	return frontier, nil
+}
+
+// listLevel counts the objects directly under prefix and returns its child prefixes. Without
+// a delimiter it counts every object under prefix.
+func (s *s3UsageScan) listLevel(ctx context.Context, prefix, delimiter string) ([]string, error) {
+	var children []string
+	paginator := s3.NewListObjectsV2Paginator(s.svc, &s3.ListObjectsV2Input{
+		Bucket:    aws.String(s.bucket),
+		Prefix:    optionalString(prefix),
+		Delimiter: optionalString(delimiter),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return children, err
+		}
+		s.tally.add(page.Contents)
+		for _, common := range page.CommonPrefixes {
+			children = append(children, aws.ToString(common.Prefix))
+		}
+	}
+	return children, nil
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *s3BucketUsageDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
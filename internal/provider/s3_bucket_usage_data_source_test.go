@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// fakeS3Lister serves ListObjectsV2 from a fixed set of keys, two entries per page.
+type fakeS3Lister struct {
+	keys []string
+	// delay is how long each call takes; slow prefixes never answer before ctx is done.
+	delay time.Duration
+	slow  map[string]bool
+
+	mu        sync.Mutex
+	inFlight  int
+	maxFlight int
+}
+
+func (f *fakeS3Lister) ListObjectsV2(ctx context.Context, input *s3.ListObjectsV2Input, _ ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	f.mu.Lock()
+	f.inFlight++
+	f.maxFlight = max(f.maxFlight, f.inFlight)
+	f.mu.Unlock()
+	defer func() {
+		f.mu.Lock()
+		f.inFlight--
+		f.mu.Unlock()
+	}()
+
+	prefix, delimiter := aws.ToString(input.Prefix), aws.ToString(input.Delimiter)
+	wait := time.After(f.delay)
+	if f.slow[prefix] {
+		wait = nil
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-wait:
+	}
+
+	// Entries are object keys, or common prefixes marked by a trailing delimiter.
+	var entries []string
+	seen := map[string]bool{}
+	for _, key := range f.keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		if delimiter != "" {
+			if index := strings.Index(key[len(prefix):], delimiter); index >= 0 {
+				key = key[:len(prefix)+index+len(delimiter)]
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+			}
+		}
+		entries = append(entries, key)
+	}
+	sort.Strings(entries)
+
+	start, _ := strconv.Atoi(aws.ToString(input.ContinuationToken))
+	end := min(start+2, len(entries))
+	out := &s3.ListObjectsV2Output{IsTruncated: aws.Bool(end < len(entries))}
+	if end < len(entries) {
+		out.NextContinuationToken = aws.String(strconv.Itoa(end))
+	}
+	for _, entry := range entries[start:end] {
+		if delimiter != "" && strings.HasSuffix(entry, delimiter) && seen[entry] {
+			out.CommonPrefixes = append(out.CommonPrefixes, awstypes.CommonPrefix{Prefix: aws.String(entry)})
+		} else {
+			out.Contents = append(out.Contents, awstypes.Object{Key: aws.String(entry), Size: aws.Int64(1)})
+		}
+	}
+	return out, nil
+}
+
+func TestS3UsageTally(t *testing.T) {
+	var tally s3UsageTally
+	tally.add([]awstypes.Object{
+		{Size: aws.Int64(10)},
+		{Size: aws.Int64(5), StorageClass: awstypes.ObjectStorageClassStandard},
+		{Size: aws.Int64(100), StorageClass: awstypes.ObjectStorageClassGlacier},
+	})
+	tally.add([]awstypes.Object{{Size: aws.Int64(1), StorageClass: awstypes.ObjectStorageClassGlacier}})
+
+	standard := tally.classes["STANDARD"]
+	if standard == nil || standard.objects != 2 || standard.bytes != 15 {
+		t.Errorf("STANDARD = %+v, want 2 objects and 15 bytes", standard)
+	}
+	glacier := tally.classes["GLACIER"]
+	if glacier == nil || glacier.objects != 2 || glacier.bytes != 101 {
+		t.Errorf("GLACIER = %+v, want 2 objects and 101 bytes", glacier)
+	}
+}
+
+func TestS3UsageScanCountsEveryObjectOnce(t *testing.T) {
+	keys := []string{"c", "root.txt", "a/1", "a/x/1", "a/x/2", "b/1", "b/y/z/1", "b/y/z/2", "b/y/z/deep/3"}
+	lister := &fakeS3Lister{keys: keys}
+	scan := s3UsageScan{svc: lister, bucket: "data"}
+
+	scanned, complete, diags := scan.run(context.Background(), "", "/", 16, time.Minute)
+	if diags.HasError() || !complete {
+		t.Fatalf("complete = %v, diagnostics = %v", complete, diags)
+	}
+	// Discovery stops after three levels with b/y/z/ still to be listed in full.
+	if scanned != 1 {
+		t.Errorf("scanned %d prefixes, want 1", scanned)
+	}
+	if standard := scan.tally.classes["STANDARD"]; standard == nil || standard.objects != int64(len(keys)) {
+		t.Errorf("STANDARD = %+v, want %d objects", standard, len(keys))
+	}
+}
+
+func TestS3UsageScanListsPrefixesInParallel(t *testing.T) {
+	var keys []string
+	for _, prefix := range []string{"a/", "b/", "c/", "d/", "e/", "f/"} {
+		keys = append(keys, prefix+"1", prefix+"2", prefix+"3")
+	}
+	lister := &fakeS3Lister{keys: keys, delay: 20 * time.Millisecond}
+	scan := s3UsageScan{svc: lister, bucket: "data"}
+
+	scanned, complete, diags := scan.run(context.Background(), "", "/", 4, time.Minute)
+	if diags.HasError() || !complete {
+		t.Fatalf("complete = %v, diagnostics = %v", complete, diags)
+	}
+	if scanned != 6 {
+		t.Errorf("scanned %d prefixes, want 6", scanned)
+	}
+	if lister.maxFlight < 2 || lister.maxFlight > 4 {
+		t.Errorf("%d listings ran at once, want between 2 and the concurrency of 4", lister.maxFlight)
+	}
+	if standard := scan.tally.classes["STANDARD"]; standard == nil || standard.objects != int64(len(keys)) {
+		t.Errorf("STANDARD = %+v, want %d objects", standard, len(keys))
+	}
+}
+
+func TestS3UsageScanStopsAtMaxScan(t *testing.T) {
+	lister := &fakeS3Lister{
+		keys: []string{"root", "fast/1", "slow/1"},
+		slow: map[string]bool{"slow/": true},
+	}
+	scan := s3UsageScan{svc: lister, bucket: "data"}
+
+	_, complete, diags := scan.run(context.Background(), "", "/", 16, 50*time.Millisecond)
+	if complete || diags.HasError() || diags.WarningsCount() != 1 {
+		t.Fatalf("complete = %v, diagnostics = %v, want one warning", complete, diags)
+	}
+	// The objects listed before the deadline are still reported.
+	if standard := scan.tally.classes["STANDARD"]; standard == nil || standard.objects != 2 {
+		t.Errorf("STANDARD = %+v, want the 2 objects outside slow/", standard)
+	}
+}
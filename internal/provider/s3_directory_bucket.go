@@ -0,0 +1,139 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// s3DirectoryBucketSuffix ends the name of every S3 Express One Zone directory bucket.
+const s3DirectoryBucketSuffix = "--x-s3"
+
+// s3DirectoryBucketNamePattern matches base-name--zone-id--x-s3, for example
+// training-data--use1-az4--x-s3 or scratch--usw2-lax1-az1--x-s3 for a Local Zone.
+var s3DirectoryBucketNamePattern = regexp.MustCompile(`^([a-z0-9][a-z0-9-]*[a-z0-9])--([a-z0-9]+(?:-[a-z0-9]+)*-az[0-9]+)--x-s3$`)
+
+// s3DirectoryBucketModel places a bucket in a single Availability Zone or Local Zone as an
+// S3 Express One Zone directory bucket.
+type s3DirectoryBucketModel struct {
+	AvailabilityZoneID types.String `tfsdk:"availability_zone_id"`
+	LocationType       types.String `tfsdk:"location_type"`
+	DataRedundancy     types.String `tfsdk:"data_redundancy"`
+}
+
+func s3DirectoryBucketSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional: true,
+		Description: "Create the bucket as an S3 Express One Zone directory bucket. The name must end in " +
+			"--<availability_zone_id>--x-s3 and the zone must be in the provider region. Directory buckets do not support bucket tags, " +
+			"so tags is only kept in state. This can only be set when the bucket is created.",
+		Attributes: map[string]schema.Attribute{
+			"availability_zone_id": schema.StringAttribute{
+				Required:    true,
+				Description: "ID of the zone that stores the data, for example use1-az4.",
+			},
+			"location_type": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(string(awstypes.LocationTypeAvailabilityZone)),
+				Description: "Kind of zone, either AvailabilityZone or LocalZone.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(awstypes.LocationTypeAvailabilityZone),
+						string(awstypes.LocationTypeLocalZone),
+					),
+				},
+			},
+			"data_redundancy": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(string(awstypes.DataRedundancySingleAvailabilityZone)),
+				Description: "How the data is stored within the zone: SingleAvailabilityZone, or SingleLocalZone for a LocalZone location.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(awstypes.DataRedundancySingleAvailabilityZone),
+						string(awstypes.DataRedundancySingleLocalZone),
+					),
+				},
+			},
+		},
+	}
+}
+
+// isS3DirectoryBucket reports whether bucket names a directory bucket. Directory buckets
+// differ from general purpose buckets in their list semantics and in the bucket settings
+// they support.
+func isS3DirectoryBucket(bucket string) bool {
+	return strings.HasSuffix(bucket, s3DirectoryBucketSuffix)
+}
+
+// validateS3DirectoryBucket checks that a directory bucket name is well formed and names the
+// configured zone, and that the location and data redundancy go together.
+func validateS3DirectoryBucket(name string, directory *s3DirectoryBucketModel) error {
+	match := s3DirectoryBucketNamePattern.FindStringSubmatch(name)
+	if match == nil || len(name) < 3 || len(name) > 63 {
+		return fmt.Errorf("directory bucket name %q must be 3 to 63 lowercase letters, digits and hyphens in the form base-name--zone-id%s", name, s3DirectoryBucketSuffix)
+	}
+	if zone := directory.AvailabilityZoneID.ValueString(); !directory.AvailabilityZoneID.IsUnknown() && match[2] != zone {
+		return fmt.Errorf("directory bucket name %q must end in --%s%s to match availability_zone_id", name, zone, s3DirectoryBucketSuffix)
+	}
+	if directory.LocationType.IsUnknown() || directory.DataRedundancy.IsUnknown() {
+		return nil
+	}
+	want := awstypes.DataRedundancySingleAvailabilityZone
+	if directory.LocationType.ValueString() == string(awstypes.LocationTypeLocalZone) {
+		want = awstypes.DataRedundancySingleLocalZone
+	}
+	if directory.DataRedundancy.ValueString() != string(want) {
+		return fmt.Errorf("location_type %s requires data_redundancy %s", directory.LocationType.ValueString(), want)
+	}
+	return nil
+}
+
+// createS3DirectoryBucket creates a directory bucket in its zone. Requests to the bucket
+// afterwards go through the zonal endpoint with CreateSession credentials, which the SDK
+// obtains and caches per client.
+func createS3DirectoryBucket(ctx context.Context, svc *s3.Client, bucket string, directory *s3DirectoryBucketModel) error {
+	_, err := svc.CreateBucket(ctx, &s3.CreateBucketInput{
+		Bucket: aws.String(bucket),
+		CreateBucketConfiguration: &awstypes.CreateBucketConfiguration{
+			Location: &awstypes.LocationInfo{
+				Name: aws.String(directory.AvailabilityZoneID.ValueString()),
+				Type: awstypes.LocationType(directory.LocationType.ValueString()),
+			},
+			Bucket: &awstypes.BucketInfo{
+				DataRedundancy: awstypes.DataRedundancy(directory.DataRedundancy.ValueString()),
+				Type:           awstypes.BucketTypeDirectory,
+			},
+		},
+	})
+	return err
+}
+
+// s3DirectoryBucketARN builds the ARN of a directory bucket, which unlike general purpose
+// buckets carries the region and the owning account.
+func s3DirectoryBucketARN(bucket, region, accountID string) string {
+	return "arn:" + awsPartition(region) + ":s3express:" + region + ":" + accountID + ":bucket/" + bucket
+}
+
+// validateS3DirectoryBucketListing rejects list parameters that directory buckets do not
+// support: they only group keys by "/" and only accept prefixes that end in it.
+func validateS3DirectoryBucketListing(prefix, delimiter string) error {
+	if delimiter != "" && delimiter != "/" {
+		return fmt.Errorf("directory buckets only support \"/\" as delimiter, got %q", delimiter)
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		return fmt.Errorf("directory buckets only support prefixes ending in \"/\", got %q", prefix)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"testing"
+
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestValidateS3DirectoryBucket(t *testing.T) {
+	zone := func(id, location, redundancy string) *s3DirectoryBucketModel {
+		return &s3DirectoryBucketModel{
+			AvailabilityZoneID: types.StringValue(id),
+			LocationType:       types.StringValue(location),
+			DataRedundancy:     types.StringValue(redundancy),
+		}
+	}
+	az := string(awstypes.LocationTypeAvailabilityZone)
+	single := string(awstypes.DataRedundancySingleAvailabilityZone)
+
+	cases := []struct {
+		name      string
+		bucket    string
+		directory *s3DirectoryBucketModel
+		wantErr   bool
+	}{
+		{"availability zone", "training-data--use1-az4--x-s3", zone("use1-az4", az, single), false},
+		{"local zone", "scratch--usw2-lax1-az1--x-s3", zone("usw2-lax1-az1", string(awstypes.LocationTypeLocalZone), string(awstypes.DataRedundancySingleLocalZone)), false},
+		{"zone mismatch", "training-data--use1-az4--x-s3", zone("use1-az5", az, single), true},
+		{"missing suffix", "training-data--use1-az4", zone("use1-az4", az, single), true},
+		{"upper case", "Training--use1-az4--x-s3", zone("use1-az4", az, single), true},
+		{"redundancy mismatch", "training-data--use1-az4--x-s3", zone("use1-az4", az, string(awstypes.DataRedundancySingleLocalZone)), true},
+		{"unknown zone", "training-data--use1-az4--x-s3", &s3DirectoryBucketModel{
+			AvailabilityZoneID: types.StringUnknown(),
+			LocationType:       types.StringValue(az),
+			DataRedundancy:     types.StringValue(single),
+		}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateS3DirectoryBucket(tc.bucket, tc.directory)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("validateS3DirectoryBucket(%q) error = %v, want error %v", tc.bucket, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateS3DirectoryBucketListing(t *testing.T) {
+	if err := validateS3DirectoryBucketListing("logs/2024/", "/"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := validateS3DirectoryBucketListing("", ""); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := validateS3DirectoryBucketListing("logs/2024", "/"); err == nil {
+		t.Error("prefix without trailing slash accepted")
+	}
+	if err := validateS3DirectoryBucketListing("", "-"); err == nil {
+		t.Error("non-slash delimiter accepted")
+	}
+}
+
+func TestS3DirectoryBucketARN(t *testing.T) {
+	want := "arn:aws:s3express:us-east-1:123456789012:bucket/data--use1-az4--x-s3"
+	if got := s3DirectoryBucketARN("data--use1-az4--x-s3", "us-east-1", "123456789012"); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
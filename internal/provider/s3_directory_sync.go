@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"io/fs"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// syncFileFilter selects the files of a directory sync by slash-separated relative path.
+// Patterns are globs where "*" and "?" stay within one path segment and "**" spans any
+// number of segments. A file is selected when it matches an include pattern (or there are
+// none) and no exclude pattern.
+type syncFileFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+func newSyncFileFilter(include, exclude []string) (syncFileFilter, error) {
+	var filter syncFileFilter
+	for _, pattern := range include {
+		compiled, err := globToRegexp(pattern)
+		if err != nil {
+			return syncFileFilter{}, err
+		}
+		filter.include = append(filter.include, compiled)
+	}
+	for _, pattern := range exclude {
+		compiled, err := globToRegexp(pattern)
+		if err != nil {
+			return syncFileFilter{}, err
+		}
+		filter.exclude = append(filter.exclude, compiled)
+	}
+	return filter, nil
+}
+
+func (f syncFileFilter) matches(rel string) bool {
+	for _, pattern := range f.exclude {
+		if pattern.MatchString(rel) {
+			return false
+		}
+	}
+	if len(f.include) == 0 {
+		return true
+	}
+	for _, pattern := range f.include {
+		if pattern.MatchString(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// globToRegexp translates a glob into an anchored regular expression.
+func globToRegexp(pattern string) (*regexp.Regexp, error) {
+	var expr strings.Builder
+	expr.WriteString("^")
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '*':
+			if i+1 < len(pattern) && pattern[i+1] == '*' {
+				i++
+				if i+1 < len(pattern) && pattern[i+1] == '/' {
+					// "**/" also matches no directories at all.
+					i++
+					expr.WriteString("(?:.*/)?")
+				} else {
+					expr.WriteString(".*")
+				}
+			} else {
+				expr.WriteString("[^/]*")
+			}
+		case '?':
+			expr.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(pattern[i:], ']')
+			if end < 0 {
+				expr.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			class := pattern[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	expr.WriteString("$")
+	return regexp.Compile(expr.String())
+}
+
+// scanSyncDirectory hashes every selected regular file under dir, keyed by its
+// slash-separated path relative to dir.
+func scanSyncDirectory(dir string, filter syncFileFilter) (map[string]string, error) {
+	files := make(map[string]string)
+	err := filepath.WalkDir(dir, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !filter.matches(rel) {
+			return nil
+		}
+		sum, err := sha256File(name)
+		if err != nil {
+			return err
+		}
+		files[rel] = sum
+		return nil
+	})
+	return files, err
+}
+
+// syncKeyPrefix normalises a key prefix so that relative paths can be appended to it.
+func syncKeyPrefix(prefix string) string {
+	if prefix == "" || strings.HasSuffix(prefix, "/") {
+		return prefix
+	}
+	return prefix + "/"
+}
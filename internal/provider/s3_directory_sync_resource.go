@@ -0,0 +1,475 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource               = &s3DirectorySyncResource{}
+	_ resource.ResourceWithConfigure  = &s3DirectorySyncResource{}
+	_ resource.ResourceWithModifyPlan = &s3DirectorySyncResource{}
+)
+
+type s3DirectorySyncResourceModel struct {
+	ID                types.String `tfsdk:"id"`
+	Last_Updated      types.String `tfsdk:"last_updated"`
+	Bucket            types.String `tfsdk:"bucket"`
+	Prefix            types.String `tfsdk:"prefix"`
+	SourceDir         types.String `tfsdk:"source_dir"`
+	Include           types.List   `tfsdk:"include"`
+	Exclude           types.List   `tfsdk:"exclude"`
+	DeleteRemoved     types.Bool   `tfsdk:"delete_removed"`
+	CacheControl      types.String `tfsdk:"cache_control"`
+	UploadConcurrency types.Int64  `tfsdk:"upload_concurrency"`
+	UseAccelerate     types.Bool   `tfsdk:"use_accelerate_endpoint"`
+	Files             types.Map    `tfsdk:"files"`
+}
+
+// NewS3DirectorySyncResource is a helper function to simplify the provider implementation.
+func NewS3DirectorySyncResource() resource.Resource {
+	return &s3DirectorySyncResource{}
+}
+
+// s3DirectorySyncResource mirrors a local directory to a key prefix in a bucket.
+type s3DirectorySyncResource struct {
+	client *ClientS3
+}
+
+func (r *s3DirectorySyncResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the developer", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *s3DirectorySyncResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_directory_sync"
+}
+
+// Schema defines the schema for the resource.
+func (r *s3DirectorySyncResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Mirrors a local directory to a key prefix in an S3 bucket. " +
+			"Only files whose content changed are uploaded, and the plan lists every file that is added, changed or removed.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"bucket": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"prefix": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				Description: "Key prefix the directory is synced to. A trailing slash is added when missing.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source_dir": schema.StringAttribute{
+				Required:    true,
+				Description: "Local directory to upload.",
+			},
+			"include": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Glob patterns of relative paths to sync. All files are synced when empty. \"**\" matches across directories.",
+			},
+			"exclude": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Glob patterns of relative paths to skip. Excluded keys are never deleted from the bucket.",
+			},
+			"delete_removed": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Delete objects under the prefix that no longer exist locally during apply. Destroy only deletes objects this resource uploaded.",
+			},
+			"cache_control": schema.StringAttribute{
+				Optional:    true,
+				Description: "Cache-Control header set on every uploaded object.",
+			},
+			"upload_concurrency": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(8),
+				Description: "Number of files uploaded in parallel.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 64),
+				},
+			},
+			"use_accelerate_endpoint": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Upload through the Transfer Acceleration endpoint. The bucket must have transfer_acceleration enabled.",
+			},
+			"files": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Hex SHA-256 of every synced file, keyed by path relative to source_dir. With delete_removed, objects found under the prefix that this resource did not upload are listed with an empty hash.",
+			},
+		},
+	}
+}
+
+// ModifyPlan hashes the local directory so the plan shows each file that changes.
+func (r *s3DirectorySyncResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to compute on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan s3DirectorySyncResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if plan.SourceDir.IsUnknown() || plan.Include.IsUnknown() || plan.Exclude.IsUnknown() {
+		return
+	}
+
+	filter, err := plan.filter(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid File Pattern", err.Error())
+		return
+	}
+	files, err := scanSyncDirectory(plan.SourceDir.ValueString(), filter)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("source_dir"), "Unable to Read Source Directory", err.Error())
+		return
+	}
+
+	planFiles, diags := types.MapValueFrom(ctx, types.StringType, files)
+	resp.Diagnostics.Append(diags...)
+	plan.Files = planFiles
+
+	var priorFiles types.Map
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("files"), &priorFiles)...)
+	}
+	if !priorFiles.Equal(plan.Files) {
+		plan.Last_Updated = types.StringUnknown()
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *s3DirectorySyncResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan s3DirectorySyncResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.sync(ctx, &plan, nil, resp.Diagnostics.AddError)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *s3DirectorySyncResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state s3DirectorySyncResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	files := make(map[string]string)
+	resp.Diagnostics.Append(state.Files.ElementsAs(ctx, &files, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	filter, err := state.filter(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid File Pattern", err.Error())
+		return
+	}
+
+	remote, err := r.listRemote(ctx, state, filter)
+	if err != nil {
+		if isS3ErrorCode(err, "NoSuchBucket") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error listing synced objects",
+			"Could not list s3://"+state.Bucket.ValueString()+"/"+state.Prefix.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	// Objects deleted outside Terraform drop out of state so the next plan uploads them again.
+	for rel := range files {
+		if _, ok := remote[rel]; !ok {
+			delete(files, rel)
+		}
+	}
+	// Unknown objects are recorded without a hash so the next plan shows them being removed.
+	// Only sync deletes them; Delete leaves them alone, since this resource never wrote them.
+	if state.DeleteRemoved.ValueBool() {
+		for rel := range remote {
+			if _, ok := files[rel]; !ok {
+				files[rel] = ""
+			}
+		}
+	}
+
+	state.Files, diags = types.MapValueFrom(ctx, types.StringType, files)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *s3DirectorySyncResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state s3DirectorySyncResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var prior map[string]string
+	resp.Diagnostics.Append(state.Files.ElementsAs(ctx, &prior, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// A new Cache-Control header has to be written to every object.
+	if !plan.CacheControl.Equal(state.CacheControl) {
+		prior = nil
+	}
+
+	r.sync(ctx, &plan, prior, resp.Diagnostics.AddError)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags := resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *s3DirectorySyncResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state s3DirectorySyncResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var files map[string]string
+	resp.Diagnostics.Append(state.Files.ElementsAs(ctx, &files, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	keyPrefix := syncKeyPrefix(state.Prefix.ValueString())
+	keys := make([]string, 0, len(files))
+	for rel, sum := range files {
+		// Objects Read found without uploading them have no hash.
+		if sum != "" {
+			keys = append(keys, keyPrefix+rel)
+		}
+	}
+	err := deleteS3Keys(ctx, r.client.S3Client, state.Bucket.ValueString(), keys)
+	if err != nil && !isS3ErrorCode(err, "NoSuchBucket") {
+		resp.Diagnostics.AddError(
+			"Error deleting synced objects",
+			"Could not delete objects under s3://"+state.Bucket.ValueString()+"/"+keyPrefix+": "+err.Error(),
+		)
+	}
+}
+
+// sync uploads every planned file whose hash differs from prior and, when delete_removed is
+// set, deletes selected objects under the prefix that are not planned.
+func (r *s3DirectorySyncResource) sync(ctx context.Context, plan *s3DirectorySyncResourceModel, prior map[string]string, addError func(string, string)) {
+	svc := r.client.S3Client
+	bucket := plan.Bucket.ValueString()
+	keyPrefix := syncKeyPrefix(plan.Prefix.ValueString())
+	sourceDir := plan.SourceDir.ValueString()
+
+	var planned map[string]string
+	if diags := plan.Files.ElementsAs(ctx, &planned, false); diags.HasError() {
+		addError("Invalid File List", "The planned file list could not be read.")
+		return
+	}
+
+	pending := make([]string, 0, len(planned))
+	for rel, sum := range planned {
+		if prior[rel] != sum {
+			pending = append(pending, rel)
+		}
+	}
+	sort.Strings(pending)
+
+	uploader := r.client.uploadClient(plan.UseAccelerate.ValueBool())
+	err := forEachConcurrently(ctx, int(plan.UploadConcurrency.ValueInt64()), pending, func(ctx context.Context, rel string) error {
+		name := filepath.Join(sourceDir, filepath.FromSlash(rel))
+		contentType, err := detectFileContentType(name)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		upload := s3ObjectUpload{
+			Bucket:       bucket,
+			Key:          keyPrefix + rel,
+			ContentType:  contentType,
+			CacheControl: plan.CacheControl.ValueString(),
+		}
+		if _, err := uploadS3File(ctx, uploader, upload, name, s3MultipartOptions{PartSize: 16 * 1024 * 1024, Concurrency: 4}); err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		return nil
+	})
+	if err != nil {
+		addError(
+			"Error uploading files",
+			"Could not sync "+sourceDir+" to s3://"+bucket+"/"+keyPrefix+": "+err.Error(),
+		)
+		return
+	}
+
+	if plan.DeleteRemoved.ValueBool() {
+		filter, err := plan.filter(ctx)
+		if err != nil {
+			addError("Invalid File Pattern", err.Error())
+			return
+		}
+		remote, err := r.listRemote(ctx, *plan, filter)
+		if err != nil {
+			addError(
+				"Error listing synced objects",
+				"Could not list s3://"+bucket+"/"+keyPrefix+": "+err.Error(),
+			)
+			return
+		}
+		var removed []string
+		for rel := range remote {
+			if _, ok := planned[rel]; !ok {
+				removed = append(removed, keyPrefix+rel)
+			}
+		}
+		if err := deleteS3Keys(ctx, svc, bucket, removed); err != nil {
+			addError(
+				"Error deleting removed files",
+				"Could not delete objects under s3://"+bucket+"/"+keyPrefix+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	plan.ID = types.StringValue(bucket + "/" + keyPrefix)
+	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
+}
+
+// listRemote returns the keys under the prefix that the include and exclude patterns select,
+// relative to the prefix.
+func (r *s3DirectorySyncResource) listRemote(ctx context.Context, model s3DirectorySyncResourceModel, filter syncFileFilter) (map[string]bool, error) {
+	keyPrefix := syncKeyPrefix(model.Prefix.ValueString())
+	paginator := s3.NewListObjectsV2Paginator(r.client.S3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(model.Bucket.ValueString()),
+		Prefix: aws.String(keyPrefix),
+	})
+
+	remote := make(map[string]bool)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range page.Contents {
+			rel := strings.TrimPrefix(aws.ToString(object.Key), keyPrefix)
+			if rel != "" && filter.matches(rel) {
+				remote[rel] = true
+			}
+		}
+	}
+	return remote, nil
+}
+
+// filter builds the file filter from the include and exclude patterns.
+func (m s3DirectorySyncResourceModel) filter(ctx context.Context) (syncFileFilter, error) {
+	var include, exclude []string
+	if diags := m.Include.ElementsAs(ctx, &include, false); diags.HasError() {
+		return syncFileFilter{}, fmt.Errorf("include must be a list of strings")
+	}
+	if diags := m.Exclude.ElementsAs(ctx, &exclude, false); diags.HasError() {
+		return syncFileFilter{}, fmt.Errorf("exclude must be a list of strings")
+	}
+	return newSyncFileFilter(include, exclude)
+}
+
+// deleteS3Keys deletes the given keys in batches of the 1000 keys DeleteObjects accepts.
+func deleteS3Keys(ctx context.Context, svc *s3.Client, bucket string, keys []string) error {
+	for start := 0; start < len(keys); start += 1000 {
+		end := min(start+1000, len(keys))
+		objects := make([]awstypes.ObjectIdentifier, 0, end-start)
+		for _, key := range keys[start:end] {
+			objects = append(objects, awstypes.ObjectIdentifier{Key: aws.String(key)})
+		}
+		out, err := svc.DeleteObjects(ctx, &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &awstypes.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return err
+		}
+		if len(out.Errors) > 0 {
+			failed := out.Errors[0]
+			return fmt.Errorf("%d objects could not be deleted, first %s: %s", len(out.Errors), aws.ToString(failed.Key), aws.ToString(failed.Message))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSyncFileFilter(t *testing.T) {
+	cases := []struct {
+		name    string
+		include []string
+		exclude []string
+		rel     string
+		want    bool
+	}{
+		{"no patterns", nil, nil, "index.html", true},
+		{"star stays in segment", []string{"*.html"}, nil, "docs/index.html", false},
+		{"double star spans segments", []string{"**/*.html"}, nil, "docs/api/index.html", true},
+		{"double star matches top level", []string{"**/*.html"}, nil, "index.html", true},
+		{"exclude wins", []string{"**"}, []string{"**/.git/**"}, "sub/.git/config", false},
+		{"character class", []string{"img/[ab].png"}, nil, "img/b.png", true},
+		{"negated class", []string{"img/[!ab].png"}, nil, "img/a.png", false},
+		{"literal dot", []string{"*.css"}, nil, "stylexcss", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			filter, err := newSyncFileFilter(tc.include, tc.exclude)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := filter.matches(tc.rel); got != tc.want {
+				t.Errorf("matches(%q) = %v, want %v", tc.rel, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestScanSyncDirectory(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"index.html":       "<html></html>",
+		"assets/site.css":  "body {}",
+		"assets/draft.tmp": "scratch",
+	} {
+		full := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	filter, err := newSyncFileFilter(nil, []string{"**/*.tmp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := scanSyncDirectory(dir, filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"index.html":      sha256Bytes([]byte("<html></html>")),
+		"assets/site.css": sha256Bytes([]byte("body {}")),
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("got %v, want %v", files, want)
+	}
+}
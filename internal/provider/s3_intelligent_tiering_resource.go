@@ -0,0 +1,347 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &s3IntelligentTieringResource{}
+	_ resource.ResourceWithConfigure   = &s3IntelligentTieringResource{}
+	_ resource.ResourceWithImportState = &s3IntelligentTieringResource{}
+)
+
+type s3IntelligentTieringResourceModel struct {
+	ID             types.String                `tfsdk:"id"`
+	Last_Updated   types.String                `tfsdk:"last_updated"`
+	Bucket         types.String                `tfsdk:"bucket"`
+	Configurations []s3IntelligentTieringModel `tfsdk:"configurations"`
+}
+
+// s3IntelligentTieringModel is one Intelligent-Tiering archive configuration.
+type s3IntelligentTieringModel struct {
+	ID                    types.String      `tfsdk:"id"`
+	Status                types.String      `tfsdk:"status"`
+	Prefix                types.String      `tfsdk:"prefix"`
+	Tags                  map[string]string `tfsdk:"tags"`
+	ArchiveAccessDays     types.Int64       `tfsdk:"archive_access_days"`
+	DeepArchiveAccessDays types.Int64       `tfsdk:"deep_archive_access_days"`
+}
+
+// NewS3IntelligentTieringResource is a helper function to simplify the provider implementation.
+func NewS3IntelligentTieringResource() resource.Resource {
+	return &s3IntelligentTieringResource{}
+}
+
+// s3IntelligentTieringResource owns every Intelligent-Tiering configuration of one bucket.
+type s3IntelligentTieringResource struct {
+	client *ClientS3
+}
+
+func (r *s3IntelligentTieringResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the developer", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *s3IntelligentTieringResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_bucket_intelligent_tiering"
+}
+
+// Schema defines the schema for the resource.
+func (r *s3IntelligentTieringResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the Intelligent-Tiering archive configurations of an S3 bucket. " +
+			"Configurations on the bucket that are not listed here are removed.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"bucket": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"configurations": schema.ListNestedAttribute{
+				Required: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Required:    true,
+							Description: "Name of the configuration, unique within the bucket.",
+						},
+						"status": schema.StringAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     stringdefault.StaticString(string(awstypes.IntelligentTieringStatusEnabled)),
+							Description: "Enabled or Disabled.",
+							Validators: []validator.String{
+								stringvalidator.OneOf(
+									string(awstypes.IntelligentTieringStatusEnabled),
+									string(awstypes.IntelligentTieringStatusDisabled),
+								),
+							},
+						},
+						"prefix": schema.StringAttribute{
+							Optional:    true,
+							Description: "Only apply to objects whose keys start with this prefix.",
+						},
+						"tags": schema.MapAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Only apply to objects carrying all of these tags.",
+						},
+						"archive_access_days": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Days without access before objects move to the Archive Access tier (90 to 730).",
+							Validators: []validator.Int64{
+								int64validator.Between(90, 730),
+								int64validator.AtLeastOneOf(path.MatchRelative().AtParent().AtName("deep_archive_access_days")),
+							},
+						},
+						"deep_archive_access_days": schema.Int64Attribute{
+							Optional:    true,
+							Description: "Days without access before objects move to the Deep Archive Access tier (180 to 730).",
+							Validators: []validator.Int64{
+								int64validator.Between(180, 730),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *s3IntelligentTieringResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan s3IntelligentTieringResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &plan, resp.Diagnostics.AddError)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *s3IntelligentTieringResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state s3IntelligentTieringResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	configurations, err := r.configurations().read(ctx, state.Bucket.ValueString(), state.Configurations)
+	if err != nil {
+		if isS3ErrorCode(err, "NoSuchBucket") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading Intelligent-Tiering configurations",
+			"Could not list Intelligent-Tiering configurations for bucket "+state.Bucket.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.ID = state.Bucket
+	state.Configurations = make([]s3IntelligentTieringModel, 0, len(configurations))
+	for _, item := range configurations {
+		state.Configurations = append(state.Configurations, flattenS3IntelligentTiering(item))
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *s3IntelligentTieringResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan s3IntelligentTieringResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &plan, resp.Diagnostics.AddError)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *s3IntelligentTieringResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state s3IntelligentTieringResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.configurations().deleteAll(ctx, state.Bucket.ValueString(), state.Configurations, resp.Diagnostics.AddError)
+}
+
+// ImportState imports the Intelligent-Tiering configurations of an existing bucket by bucket name.
+func (r *s3IntelligentTieringResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), req.ID)...)
+}
+
+// write puts every planned configuration and deletes any other configuration on the bucket.
+func (r *s3IntelligentTieringResource) write(ctx context.Context, plan *s3IntelligentTieringResourceModel, addError func(string, string)) {
+	r.configurations().write(ctx, plan.Bucket.ValueString(), plan.Configurations, addError)
+	plan.ID = plan.Bucket
+	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
+}
+
+// configurations returns the operations on the Intelligent-Tiering configurations of a bucket.
+func (r *s3IntelligentTieringResource) configurations() s3BucketConfigurationSet[s3IntelligentTieringModel, awstypes.IntelligentTieringConfiguration] {
+	svc := r.client.S3Client
+	return s3BucketConfigurationSet[s3IntelligentTieringModel, awstypes.IntelligentTieringConfiguration]{
+		kind:    "Intelligent-Tiering",
+		modelID: func(item s3IntelligentTieringModel) string { return item.ID.ValueString() },
+		id:      func(config awstypes.IntelligentTieringConfiguration) string { return aws.ToString(config.Id) },
+		put: func(ctx context.Context, bucket string, item s3IntelligentTieringModel) error {
+			_, err := svc.PutBucketIntelligentTieringConfiguration(ctx, &s3.PutBucketIntelligentTieringConfigurationInput{
+				Bucket:                          aws.String(bucket),
+				Id:                              aws.String(item.ID.ValueString()),
+				IntelligentTieringConfiguration: expandS3IntelligentTiering(item),
+			})
+			return err
+		},
+		delete: func(ctx context.Context, bucket, id string) error {
+			_, err := svc.DeleteBucketIntelligentTieringConfiguration(ctx, &s3.DeleteBucketIntelligentTieringConfigurationInput{
+				Bucket: aws.String(bucket),
+				Id:     aws.String(id),
+			})
+			return err
+		},
+		list: func(ctx context.Context, bucket string) ([]awstypes.IntelligentTieringConfiguration, error) {
+			return listS3IntelligentTieringConfigurations(ctx, svc, bucket)
+		},
+	}
+}
+
+// listS3IntelligentTieringConfigurations returns every Intelligent-Tiering configuration on the bucket.
+func listS3IntelligentTieringConfigurations(ctx context.Context, svc *s3.Client, bucket string) ([]awstypes.IntelligentTieringConfiguration, error) {
+	var configurations []awstypes.IntelligentTieringConfiguration
+	input := &s3.ListBucketIntelligentTieringConfigurationsInput{Bucket: aws.String(bucket)}
+	for {
+		out, err := svc.ListBucketIntelligentTieringConfigurations(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		configurations = append(configurations, out.IntelligentTieringConfigurationList...)
+		if !aws.ToBool(out.IsTruncated) || out.NextContinuationToken == nil {
+			return configurations, nil
+		}
+		input.ContinuationToken = out.NextContinuationToken
+	}
+}
+
+func expandS3IntelligentTiering(item s3IntelligentTieringModel) *awstypes.IntelligentTieringConfiguration {
+	config := &awstypes.IntelligentTieringConfiguration{
+		Id:     aws.String(item.ID.ValueString()),
+		Status: awstypes.IntelligentTieringStatus(item.Status.ValueString()),
+	}
+	if !item.ArchiveAccessDays.IsNull() {
+		config.Tierings = append(config.Tierings, awstypes.Tiering{
+			AccessTier: awstypes.IntelligentTieringAccessTierArchiveAccess,
+			Days:       aws.Int32(int32(item.ArchiveAccessDays.ValueInt64())),
+		})
+	}
+	if !item.DeepArchiveAccessDays.IsNull() {
+		config.Tierings = append(config.Tierings, awstypes.Tiering{
+			AccessTier: awstypes.IntelligentTieringAccessTierDeepArchiveAccess,
+			Days:       aws.Int32(int32(item.DeepArchiveAccessDays.ValueInt64())),
+		})
+	}
+
+	tags := s3TagsFromMap(item.Tags)
+	switch {
+	case len(tags) > 1 || (len(tags) == 1 && !item.Prefix.IsNull()):
+		config.Filter = &awstypes.IntelligentTieringFilter{
+			And: &awstypes.IntelligentTieringAndOperator{Prefix: item.Prefix.ValueStringPointer(), Tags: tags},
+		}
+	case len(tags) == 1:
+		config.Filter = &awstypes.IntelligentTieringFilter{Tag: &tags[0]}
+	case !item.Prefix.IsNull():
+		config.Filter = &awstypes.IntelligentTieringFilter{Prefix: item.Prefix.ValueStringPointer()}
+	}
+	return config
+}
+
+func flattenS3IntelligentTiering(config awstypes.IntelligentTieringConfiguration) s3IntelligentTieringModel {
+	item := s3IntelligentTieringModel{
+		ID:                    types.StringPointerValue(config.Id),
+		Status:                types.StringValue(string(config.Status)),
+		Prefix:                types.StringNull(),
+		ArchiveAccessDays:     types.Int64Null(),
+		DeepArchiveAccessDays: types.Int64Null(),
+	}
+	for _, tiering := range config.Tierings {
+		switch tiering.AccessTier {
+		case awstypes.IntelligentTieringAccessTierArchiveAccess:
+			item.ArchiveAccessDays = types.Int64Value(int64(aws.ToInt32(tiering.Days)))
+		case awstypes.IntelligentTieringAccessTierDeepArchiveAccess:
+			item.DeepArchiveAccessDays = types.Int64Value(int64(aws.ToInt32(tiering.Days)))
+		}
+	}
+	if filter := config.Filter; filter != nil {
+		switch {
+		case filter.And != nil:
+			item.Prefix = types.StringPointerValue(filter.And.Prefix)
+			item.Tags = s3TagsToMap(filter.And.Tags)
+		case filter.Tag != nil:
+			item.Tags = s3TagsToMap([]awstypes.Tag{*filter.Tag})
+		case filter.Prefix != nil:
+			item.Prefix = types.StringPointerValue(filter.Prefix)
+		}
+	}
+	return item
+}
@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestS3IntelligentTieringRoundTrip(t *testing.T) {
+	cases := map[string]struct {
+		item         s3IntelligentTieringModel
+		wantTierings []awstypes.Tiering
+	}{
+		"archive only": {
+			item: s3IntelligentTieringModel{
+				ID:                    types.StringValue("archive"),
+				Status:                types.StringValue(string(awstypes.IntelligentTieringStatusEnabled)),
+				Prefix:                types.StringNull(),
+				ArchiveAccessDays:     types.Int64Value(90),
+				DeepArchiveAccessDays: types.Int64Null(),
+			},
+			wantTierings: []awstypes.Tiering{
+				{AccessTier: awstypes.IntelligentTieringAccessTierArchiveAccess, Days: aws.Int32(90)},
+			},
+		},
+		"both tiers with prefix and tags": {
+			item: s3IntelligentTieringModel{
+				ID:                    types.StringValue("cold"),
+				Status:                types.StringValue(string(awstypes.IntelligentTieringStatusDisabled)),
+				Prefix:                types.StringValue("data/"),
+				Tags:                  map[string]string{"tier": "cold"},
+				ArchiveAccessDays:     types.Int64Value(125),
+				DeepArchiveAccessDays: types.Int64Value(180),
+			},
+			wantTierings: []awstypes.Tiering{
+				{AccessTier: awstypes.IntelligentTieringAccessTierArchiveAccess, Days: aws.Int32(125)},
+				{AccessTier: awstypes.IntelligentTieringAccessTierDeepArchiveAccess, Days: aws.Int32(180)},
+			},
+		},
+		"deep archive with one tag": {
+			item: s3IntelligentTieringModel{
+				ID:                    types.StringValue("deep"),
+				Status:                types.StringValue(string(awstypes.IntelligentTieringStatusEnabled)),
+				Prefix:                types.StringNull(),
+				Tags:                  map[string]string{"team": "data"},
+				ArchiveAccessDays:     types.Int64Null(),
+				DeepArchiveAccessDays: types.Int64Value(730),
+			},
+			wantTierings: []awstypes.Tiering{
+				{AccessTier: awstypes.IntelligentTieringAccessTierDeepArchiveAccess, Days: aws.Int32(730)},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			config := expandS3IntelligentTiering(tc.item)
+			if !reflect.DeepEqual(config.Tierings, tc.wantTierings) {
+				t.Fatalf("tierings = %+v, want %+v", config.Tierings, tc.wantTierings)
+			}
+			if got := flattenS3IntelligentTiering(*config); !reflect.DeepEqual(got, tc.item) {
+				t.Errorf("round trip changed the configuration:\ngot  %+v\nwant %+v", got, tc.item)
+			}
+		})
+	}
+}
+
+func TestFlattenS3IntelligentTieringIgnoresTierOrder(t *testing.T) {
+	// S3 does not promise to return the tierings in the order they were put.
+	item := flattenS3IntelligentTiering(awstypes.IntelligentTieringConfiguration{
+		Id:     aws.String("cold"),
+		Status: awstypes.IntelligentTieringStatusEnabled,
+		Tierings: []awstypes.Tiering{
+			{AccessTier: awstypes.IntelligentTieringAccessTierDeepArchiveAccess, Days: aws.Int32(180)},
+			{AccessTier: awstypes.IntelligentTieringAccessTierArchiveAccess, Days: aws.Int32(90)},
+		},
+	})
+	if item.ArchiveAccessDays.ValueInt64() != 90 || item.DeepArchiveAccessDays.ValueInt64() != 180 {
+		t.Errorf("archive days = %v, deep archive days = %v", item.ArchiveAccessDays, item.DeepArchiveAccessDays)
+	}
+}
@@ -0,0 +1,246 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+// isS3ErrorCode reports whether err is an S3 API error carrying one of the given codes.
+// Several bucket sub-resources signal "not configured" with an error instead of an empty
+// response, so Read uses this to tell an unset configuration from a real failure.
+func isS3ErrorCode(err error, codes ...string) bool {
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.ErrorCode() == code {
+			return true
+		}
+	}
+	return false
+}
+
+// orderByPriorIDs returns refreshed configurations in the order they already have in state,
+// followed by any that exist only remotely. S3 lists bucket configurations sorted by ID, so
+// without this every Read would report a reordering of the user's list as drift.
+func orderByPriorIDs[T any](items []T, priorIDs []string, id func(T) string) []T {
+	byID := make(map[string]T, len(items))
+	for _, item := range items {
+		byID[id(item)] = item
+	}
+
+	ordered := make([]T, 0, len(items))
+	for _, priorID := range priorIDs {
+		if item, ok := byID[priorID]; ok {
+			ordered = append(ordered, item)
+			delete(byID, priorID)
+		}
+	}
+	for _, item := range items {
+		if _, ok := byID[id(item)]; ok {
+			ordered = append(ordered, item)
+		}
+	}
+	return ordered
+}
+
+// s3BucketConfigurationSet manages all configurations of one ID-keyed kind on a bucket, such
+// as its metrics or inventory configurations. M is the Terraform model of a configuration and
+// C its S3 type.
+type s3BucketConfigurationSet[M, C any] struct {
+	// kind names the configurations in diagnostics, for example "metrics".
+	kind    string
+	modelID func(M) string
+	id      func(C) string
+	put     func(ctx context.Context, bucket string, item M) error
+	delete  func(ctx context.Context, bucket, id string) error
+	list    func(ctx context.Context, bucket string) ([]C, error)
+}
+
+// write puts every planned configuration and deletes any other configuration on the bucket.
+func (s s3BucketConfigurationSet[M, C]) write(ctx context.Context, bucket string, planned []M, addError func(string, string)) {
+	plannedIDs := make(map[string]bool, len(planned))
+	for _, item := range planned {
+		plannedIDs[s.modelID(item)] = true
+		if err := s.put(ctx, bucket, item); err != nil {
+			addError(
+				"Error writing "+s.kind+" configuration",
+				"Could not put configuration "+s.modelID(item)+" on bucket "+bucket+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	existing, err := s.list(ctx, bucket)
+	if err != nil {
+		addError(
+			"Error reading "+s.kind+" configurations",
+			"Could not list "+s.kind+" configurations for bucket "+bucket+": "+err.Error(),
+		)
+		return
+	}
+	for _, item := range existing {
+		if plannedIDs[s.id(item)] {
+			continue
+		}
+		if err := s.delete(ctx, bucket, s.id(item)); err != nil {
+			addError(
+				"Error deleting "+s.kind+" configuration",
+				"Could not delete configuration "+s.id(item)+" from bucket "+bucket+": "+err.Error(),
+			)
+			return
+		}
+	}
+}
+
+// read lists the configurations on the bucket in the order prior, the state, has them.
+func (s s3BucketConfigurationSet[M, C]) read(ctx context.Context, bucket string, prior []M) ([]C, error) {
+	configurations, err := s.list(ctx, bucket)
+	if err != nil {
+		return nil, err
+	}
+	priorIDs := make([]string, 0, len(prior))
+	for _, item := range prior {
+		priorIDs = append(priorIDs, s.modelID(item))
+	}
+	return orderByPriorIDs(configurations, priorIDs, s.id), nil
+}
+
+// deleteAll deletes the given configurations, skipping those that are already gone.
+func (s s3BucketConfigurationSet[M, C]) deleteAll(ctx context.Context, bucket string, items []M, addError func(string, string)) {
+	for _, item := range items {
+		err := s.delete(ctx, bucket, s.modelID(item))
+		if err != nil && !isS3ErrorCode(err, "NoSuchBucket", "NoSuchConfiguration") {
+			addError(
+				"Error deleting "+s.kind+" configuration",
+				"Could not delete configuration "+s.modelID(item)+" from bucket "+bucket+": "+err.Error(),
+			)
+			return
+		}
+	}
+}
+
+// s3BucketARN builds the ARN of a bucket in the partition of its region.
+func s3BucketARN(bucket, region string) string {
+	return "arn:" + awsPartition(region) + ":s3:::" + bucket
+}
+
+// awsPartitions are the partitions awsPartition knows about.
+var awsPartitions = []string{"aws", "aws-cn", "aws-us-gov"}
+
+// awsPartition returns the partition a region belongs to, aws for unknown regions.
+func awsPartition(region string) string {
+	switch {
+	case strings.HasPrefix(region, "cn-"):
+		return "aws-cn"
+	case strings.HasPrefix(region, "us-gov-"):
+		return "aws-us-gov"
+	default:
+		return "aws"
+	}
+}
+
+// awsPartitionDNSSuffix returns the domain service endpoints of a partition live under,
+// which service principals and kms:ViaService values end in.
+func awsPartitionDNSSuffix(partition string) string {
+	if partition == "aws-cn" {
+		return "amazonaws.com.cn"
+	}
+	return "amazonaws.com"
+}
+
+// s3TagsFromMap converts a Terraform tag map into an S3 tag set, sorted by key so that
+// requests built from the same map are always identical.
+func s3TagsFromMap(tags map[string]string) []awstypes.Tag {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tagSet := make([]awstypes.Tag, 0, len(keys))
+	for _, key := range keys {
+		tagSet = append(tagSet, awstypes.Tag{Key: aws.String(key), Value: aws.String(tags[key])})
+	}
+	return tagSet
+}
+
+// s3TagsToMap converts an S3 tag set into a Terraform tag map. An empty set becomes nil
+// so that an unset tags attribute stays null in state.
+func s3TagsToMap(tagSet []awstypes.Tag) map[string]string {
+	if len(tagSet) == 0 {
+		return nil
+	}
+	tags := make(map[string]string, len(tagSet))
+	for _, tag := range tagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags
+}
+
+// forEachConcurrently calls fn for every item using at most limit goroutines. It stops handing
+// out work after the first failure and returns that error once running calls have finished.
+func forEachConcurrently[T any](ctx context.Context, limit int, items []T, fn func(context.Context, T) error) error {
+	if limit < 1 {
+		limit = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	work := make(chan T)
+	for worker := 0; worker < limit; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range work {
+				if err := fn(ctx, item); err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, item := range items {
+		select {
+		case work <- item:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// equivalentPolicyJSON reports whether two JSON policy documents are semantically equal,
+// so that a policy S3 returns reformatted is not reported as drift.
+func equivalentPolicyJSON(a, b string) bool {
+	var left, right any
+	if json.Unmarshal([]byte(a), &left) != nil || json.Unmarshal([]byte(b), &right) != nil {
+		return false
+	}
+	return reflect.DeepEqual(left, right)
+}
@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestOrderByPriorIDs(t *testing.T) {
+	identity := func(s string) string { return s }
+
+	cases := []struct {
+		name     string
+		items    []string
+		priorIDs []string
+		want     []string
+	}{
+		{"keeps prior order", []string{"a", "b", "c"}, []string{"c", "a", "b"}, []string{"c", "a", "b"}},
+		{"appends remote only", []string{"a", "b", "c"}, []string{"b"}, []string{"b", "a", "c"}},
+		{"drops removed", []string{"a"}, []string{"b", "a"}, []string{"a"}},
+		{"no prior state", []string{"b", "a"}, nil, []string{"b", "a"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := orderByPriorIDs(tc.items, tc.priorIDs, identity)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestS3BucketConfigurationSetWrite(t *testing.T) {
+	remote := map[string]bool{"keep": true, "stale": true}
+	var puts, deletes []string
+	set := s3BucketConfigurationSet[string, string]{
+		kind:    "test",
+		modelID: func(item string) string { return item },
+		id:      func(config string) string { return config },
+		put: func(_ context.Context, _ string, item string) error {
+			puts = append(puts, item)
+			remote[item] = true
+			return nil
+		},
+		delete: func(_ context.Context, _ string, id string) error {
+			deletes = append(deletes, id)
+			delete(remote, id)
+			return nil
+		},
+		list: func(context.Context, string) ([]string, error) {
+			var ids []string
+			for id := range remote {
+				ids = append(ids, id)
+			}
+			sort.Strings(ids)
+			return ids, nil
+		},
+	}
+
+	set.write(context.Background(), "bucket", []string{"new", "keep"}, func(summary, detail string) {
+		t.Fatalf("%s: %s", summary, detail)
+	})
+	if !reflect.DeepEqual(puts, []string{"new", "keep"}) || !reflect.DeepEqual(deletes, []string{"stale"}) {
+		t.Errorf("puts = %v, deletes = %v", puts, deletes)
+	}
+
+	got, err := set.read(context.Background(), "bucket", []string{"new", "keep"})
+	if err != nil || !reflect.DeepEqual(got, []string{"new", "keep"}) {
+		t.Errorf("read = %v, %v, want the prior order", got, err)
+	}
+}
+
+func TestS3TagsRoundTrip(t *testing.T) {
+	tags := map[string]string{"team": "data", "env": "prod"}
+
+	tagSet := s3TagsFromMap(tags)
+	if len(tagSet) != 2 || aws.ToString(tagSet[0].Key) != "env" {
+		t.Fatalf("expected tag set sorted by key, got %v", tagSet)
+	}
+	if got := s3TagsToMap(tagSet); !reflect.DeepEqual(got, tags) {
+		t.Errorf("got %v, want %v", got, tags)
+	}
+	if got := s3TagsToMap([]awstypes.Tag{}); got != nil {
+		t.Errorf("expected nil map for empty tag set, got %v", got)
+	}
+}
+
+func TestEquivalentPolicyJSON(t *testing.T) {
+	compact := `{"Version":"2012-10-17","Statement":[{"Effect":"Allow","Action":"s3:GetObject"}]}`
+	indented := "{\n  \"Statement\": [{\"Action\": \"s3:GetObject\", \"Effect\": \"Allow\"}],\n  \"Version\": \"2012-10-17\"\n}"
+	if !equivalentPolicyJSON(compact, indented) {
+		t.Error("reformatted policy reported as different")
+	}
+	if equivalentPolicyJSON(compact, `{"Version":"2012-10-17","Statement":[]}`) {
+		t.Error("different policies reported as equivalent")
+	}
+	if equivalentPolicyJSON("", compact) {
+		t.Error("missing policy reported as equivalent")
+	}
+}
+
+func TestAWSPartitionDNSSuffix(t *testing.T) {
+	cases := map[string]string{
+		"cn-northwest-1": "amazonaws.com.cn",
+		"us-gov-west-1":  "amazonaws.com",
+		"eu-west-1":      "amazonaws.com",
+	}
+	for region, want := range cases {
+		if got := awsPartitionDNSSuffix(awsPartition(region)); got != want {
+			t.Errorf("DNS suffix of %s = %q, want %q", region, got, want)
+		}
+	}
+}
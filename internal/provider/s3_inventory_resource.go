@@ -0,0 +1,478 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &s3InventoryResource{}
+	_ resource.ResourceWithConfigure   = &s3InventoryResource{}
+	_ resource.ResourceWithImportState = &s3InventoryResource{}
+	_ resource.ResourceWithModifyPlan  = &s3InventoryResource{}
+)
+
+const (
+	s3InventoryEncryptionS3  = "SSE-S3"
+	s3InventoryEncryptionKMS = "SSE-KMS"
+)
+
+type s3InventoryResourceModel struct {
+	ID             types.String       `tfsdk:"id"`
+	Last_Updated   types.String       `tfsdk:"last_updated"`
+	Bucket         types.String       `tfsdk:"bucket"`
+	Configurations []s3InventoryModel `tfsdk:"configurations"`
+}
+
+// s3InventoryModel is one inventory configuration.
+type s3InventoryModel struct {
+	ID                     types.String                `tfsdk:"id"`
+	Enabled                types.Bool                  `tfsdk:"enabled"`
+	Frequency              types.String                `tfsdk:"frequency"`
+	IncludedObjectVersions types.String                `tfsdk:"included_object_versions"`
+	OptionalFields         []types.String              `tfsdk:"optional_fields"`
+	Prefix                 types.String                `tfsdk:"prefix"`
+	Destination            s3InventoryDestinationModel `tfsdk:"destination"`
+}
+
+// s3InventoryDestinationModel is where and how an inventory report is written.
+type s3InventoryDestinationModel struct {
+	BucketARN  types.String `tfsdk:"bucket_arn"`
+	AccountID  types.String `tfsdk:"account_id"`
+	Format     types.String `tfsdk:"format"`
+	Prefix     types.String `tfsdk:"prefix"`
+	Encryption types.String `tfsdk:"encryption"`
+	KMSKeyID   types.String `tfsdk:"kms_key_id"`
+}
+
+// NewS3InventoryResource is a helper function to simplify the provider implementation.
+func NewS3InventoryResource() resource.Resource {
+	return &s3InventoryResource{}
+}
+
+// s3InventoryResource owns every inventory configuration of one bucket.
+type s3InventoryResource struct {
+	client *ClientS3
+}
+
+func (r *s3InventoryResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the developer", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *s3InventoryResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_bucket_inventory"
+}
+
+// Schema defines the schema for the resource.
+func (r *s3InventoryResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	optionalFields := make([]string, 0, len(awstypes.InventoryOptionalField("").Values()))
+	for _, field := range awstypes.InventoryOptionalField("").Values() {
+		optionalFields = append(optionalFields, string(field))
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Manages the S3 Inventory configurations of a bucket. " +
+			"Configurations on the bucket that are not listed here are removed.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"bucket": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"configurations": schema.ListNestedAttribute{
+				Required: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Required:    true,
+							Description: "Name of the configuration, unique within the bucket.",
+						},
+						"enabled": schema.BoolAttribute{
+							Optional: true,
+							Computed: true,
+							Default:  booldefault.StaticBool(true),
+						},
+						"frequency": schema.StringAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     stringdefault.StaticString(string(awstypes.InventoryFrequencyDaily)),
+							Description: "Daily or Weekly.",
+							Validators: []validator.String{
+								stringvalidator.OneOf(
+									string(awstypes.InventoryFrequencyDaily),
+									string(awstypes.InventoryFrequencyWeekly),
+								),
+							},
+						},
+						"included_object_versions": schema.StringAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     stringdefault.StaticString(string(awstypes.InventoryIncludedObjectVersionsCurrent)),
+							Description: "Current or All.",
+							Validators: []validator.String{
+								stringvalidator.OneOf(
+									string(awstypes.InventoryIncludedObjectVersionsCurrent),
+									string(awstypes.InventoryIncludedObjectVersionsAll),
+								),
+							},
+						},
+						"optional_fields": schema.SetAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Extra columns in the report, for example Size, LastModifiedDate or StorageClass.",
+							Validators: []validator.Set{
+								setvalidator.ValueStringsAre(stringvalidator.OneOf(optionalFields...)),
+							},
+						},
+						"prefix": schema.StringAttribute{
+							Optional:    true,
+							Description: "Only list objects whose keys start with this prefix.",
+						},
+						"destination": schema.SingleNestedAttribute{
+							Required: true,
+							Attributes: map[string]schema.Attribute{
+								"bucket_arn": schema.StringAttribute{
+									Required:    true,
+									Description: "ARN of the bucket the reports are written to.",
+									Validators: []validator.String{
+										stringvalidator.RegexMatches(regexp.MustCompile(`^arn:[^:]+:s3:::.+`), "must be an S3 bucket ARN"),
+									},
+								},
+								"account_id": schema.StringAttribute{
+									Optional:    true,
+									Description: "Expected owner of the destination bucket.",
+								},
+								"format": schema.StringAttribute{
+									Optional:    true,
+									Computed:    true,
+									Default:     stringdefault.StaticString(string(awstypes.InventoryFormatCsv)),
+									Description: "CSV, ORC or Parquet.",
+									Validators: []validator.String{
+										stringvalidator.OneOf(
+											string(awstypes.InventoryFormatCsv),
+											string(awstypes.InventoryFormatOrc),
+											string(awstypes.InventoryFormatParquet),
+										),
+									},
+								},
+								"prefix": schema.StringAttribute{
+									Optional:    true,
+									Description: "Key prefix of the reports in the destination bucket.",
+								},
+								"encryption": schema.StringAttribute{
+									Optional:    true,
+									Description: "Encrypt the reports with SSE-S3 or SSE-KMS.",
+									Validators: []validator.String{
+										stringvalidator.OneOf(s3InventoryEncryptionS3, s3InventoryEncryptionKMS),
+									},
+								},
+								"kms_key_id": schema.StringAttribute{
+									Optional:    true,
+									Description: "KMS key ARN used when encryption is SSE-KMS.",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ModifyPlan checks that SSE-KMS destinations name a key and that nothing else does.
+func (r *s3InventoryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan s3InventoryResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for index, item := range plan.Configurations {
+		destination := item.Destination
+		if destination.Encryption.IsUnknown() || destination.KMSKeyID.IsUnknown() {
+			continue
+		}
+		attribute := path.Root("configurations").AtListIndex(index).AtName("destination")
+		switch {
+		case destination.Encryption.ValueString() == s3InventoryEncryptionKMS && destination.KMSKeyID.IsNull():
+			resp.Diagnostics.AddAttributeError(
+				attribute.AtName("kms_key_id"),
+				"Missing KMS Key",
+				"Inventory configuration "+item.ID.ValueString()+" encrypts reports with SSE-KMS and must set kms_key_id.",
+			)
+		case destination.Encryption.ValueString() != s3InventoryEncryptionKMS && !destination.KMSKeyID.IsNull():
+			resp.Diagnostics.AddAttributeError(
+				attribute.AtName("kms_key_id"),
+				"Unused KMS Key",
+				"Inventory configuration "+item.ID.ValueString()+" sets kms_key_id, which requires encryption = \"SSE-KMS\".",
+			)
+		}
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *s3InventoryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan s3InventoryResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &plan, resp.Diagnostics.AddError)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *s3InventoryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state s3InventoryResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	configurations, err := r.configurations().read(ctx, state.Bucket.ValueString(), state.Configurations)
+	if err != nil {
+		if isS3ErrorCode(err, "NoSuchBucket") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading inventory configurations",
+			"Could not list inventory configurations for bucket "+state.Bucket.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	prior := make(map[string]s3InventoryModel, len(state.Configurations))
+	for _, item := range state.Configurations {
+		prior[item.ID.ValueString()] = item
+	}
+
+	state.ID = state.Bucket
+	state.Configurations = make([]s3InventoryModel, 0, len(configurations))
+	for _, config := range configurations {
+		item := flattenS3Inventory(config)
+		// An explicitly empty optional_fields reads back as none at all.
+		if priorItem, ok := prior[item.ID.ValueString()]; ok && item.OptionalFields == nil && priorItem.OptionalFields != nil {
+			item.OptionalFields = []types.String{}
+		}
+		state.Configurations = append(state.Configurations, item)
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *s3InventoryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan s3InventoryResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &plan, resp.Diagnostics.AddError)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *s3InventoryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state s3InventoryResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.configurations().deleteAll(ctx, state.Bucket.ValueString(), state.Configurations, resp.Diagnostics.AddError)
+}
+
+// ImportState imports the inventory configurations of an existing bucket by bucket name.
+func (r *s3InventoryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), req.ID)...)
+}
+
+// write puts every planned configuration and deletes any other configuration on the bucket.
+func (r *s3InventoryResource) write(ctx context.Context, plan *s3InventoryResourceModel, addError func(string, string)) {
+	r.configurations().write(ctx, plan.Bucket.ValueString(), plan.Configurations, addError)
+	plan.ID = plan.Bucket
+	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
+}
+
+// configurations returns the operations on the inventory configurations of a bucket.
+func (r *s3InventoryResource) configurations() s3BucketConfigurationSet[s3InventoryModel, awstypes.InventoryConfiguration] {
+	svc := r.client.S3Client
+	return s3BucketConfigurationSet[s3InventoryModel, awstypes.InventoryConfiguration]{
+		kind:    "inventory",
+		modelID: func(item s3InventoryModel) string { return item.ID.ValueString() },
+		id:      func(config awstypes.InventoryConfiguration) string { return aws.ToString(config.Id) },
+		put: func(ctx context.Context, bucket string, item s3InventoryModel) error {
+			_, err := svc.PutBucketInventoryConfiguration(ctx, &s3.PutBucketInventoryConfigurationInput{
+				Bucket:                 aws.String(bucket),
+				Id:                     aws.String(item.ID.ValueString()),
+				InventoryConfiguration: expandS3Inventory(item),
+			})
+			return err
+		},
+		delete: func(ctx context.Context, bucket, id string) error {
+			_, err := svc.DeleteBucketInventoryConfiguration(ctx, &s3.DeleteBucketInventoryConfigurationInput{
+				Bucket: aws.String(bucket),
+				Id:     aws.String(id),
+			})
+			return err
+		},
+		list: func(ctx context.Context, bucket string) ([]awstypes.InventoryConfiguration, error) {
+			return listS3InventoryConfigurations(ctx, svc, bucket)
+		},
+	}
+}
+
+// listS3InventoryConfigurations returns every inventory configuration on the bucket.
+func listS3InventoryConfigurations(ctx context.Context, svc *s3.Client, bucket string) ([]awstypes.InventoryConfiguration, error) {
+	var configurations []awstypes.InventoryConfiguration
+	input := &s3.ListBucketInventoryConfigurationsInput{Bucket: aws.String(bucket)}
+	for {
+		out, err := svc.ListBucketInventoryConfigurations(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		configurations = append(configurations, out.InventoryConfigurationList...)
+		if !aws.ToBool(out.IsTruncated) || out.NextContinuationToken == nil {
+			return configurations, nil
+		}
+		input.ContinuationToken = out.NextContinuationToken
+	}
+}
+
+func expandS3Inventory(item s3InventoryModel) *awstypes.InventoryConfiguration {
+	destination := &awstypes.InventoryS3BucketDestination{
+		Bucket:    aws.String(item.Destination.BucketARN.ValueString()),
+		Format:    awstypes.InventoryFormat(item.Destination.Format.ValueString()),
+		AccountId: item.Destination.AccountID.ValueStringPointer(),
+		Prefix:    item.Destination.Prefix.ValueStringPointer(),
+	}
+	switch item.Destination.Encryption.ValueString() {
+	case s3InventoryEncryptionS3:
+		destination.Encryption = &awstypes.InventoryEncryption{SSES3: &awstypes.SSES3{}}
+	case s3InventoryEncryptionKMS:
+		destination.Encryption = &awstypes.InventoryEncryption{
+			SSEKMS: &awstypes.SSEKMS{KeyId: item.Destination.KMSKeyID.ValueStringPointer()},
+		}
+	}
+
+	config := &awstypes.InventoryConfiguration{
+		Id:                     aws.String(item.ID.ValueString()),
+		IsEnabled:              aws.Bool(item.Enabled.ValueBool()),
+		Schedule:               &awstypes.InventorySchedule{Frequency: awstypes.InventoryFrequency(item.Frequency.ValueString())},
+		IncludedObjectVersions: awstypes.InventoryIncludedObjectVersions(item.IncludedObjectVersions.ValueString()),
+		Destination:            &awstypes.InventoryDestination{S3BucketDestination: destination},
+	}
+	for _, field := range item.OptionalFields {
+		config.OptionalFields = append(config.OptionalFields, awstypes.InventoryOptionalField(field.ValueString()))
+	}
+	if !item.Prefix.IsNull() {
+		config.Filter = &awstypes.InventoryFilter{Prefix: item.Prefix.ValueStringPointer()}
+	}
+	return config
+}
+
+func flattenS3Inventory(config awstypes.InventoryConfiguration) s3InventoryModel {
+	item := s3InventoryModel{
+		ID:                     types.StringPointerValue(config.Id),
+		Enabled:                types.BoolValue(aws.ToBool(config.IsEnabled)),
+		IncludedObjectVersions: types.StringValue(string(config.IncludedObjectVersions)),
+		Prefix:                 types.StringNull(),
+		Destination: s3InventoryDestinationModel{
+			BucketARN:  types.StringNull(),
+			AccountID:  types.StringNull(),
+			Format:     types.StringNull(),
+			Prefix:     types.StringNull(),
+			Encryption: types.StringNull(),
+			KMSKeyID:   types.StringNull(),
+		},
+	}
+	if config.Schedule != nil {
+		item.Frequency = types.StringValue(string(config.Schedule.Frequency))
+	}
+	for _, field := range config.OptionalFields {
+		item.OptionalFields = append(item.OptionalFields, types.StringValue(string(field)))
+	}
+	if config.Filter != nil {
+		item.Prefix = types.StringPointerValue(config.Filter.Prefix)
+	}
+	if config.Destination != nil && config.Destination.S3BucketDestination != nil {
+		destination := config.Destination.S3BucketDestination
+		item.Destination.BucketARN = types.StringPointerValue(destination.Bucket)
+		item.Destination.AccountID = types.StringPointerValue(destination.AccountId)
+		item.Destination.Format = types.StringValue(string(destination.Format))
+		item.Destination.Prefix = types.StringPointerValue(destination.Prefix)
+		if encryption := destination.Encryption; encryption != nil {
+			switch {
+			case encryption.SSEKMS != nil:
+				item.Destination.Encryption = types.StringValue(s3InventoryEncryptionKMS)
+				item.Destination.KMSKeyID = types.StringPointerValue(encryption.SSEKMS.KeyId)
+			case encryption.SSES3 != nil:
+				item.Destination.Encryption = types.StringValue(s3InventoryEncryptionS3)
+			}
+		}
+	}
+	return item
+}
@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestS3InventoryRoundTrip(t *testing.T) {
+	item := s3InventoryModel{
+		ID:                     types.StringValue("daily"),
+		Enabled:                types.BoolValue(true),
+		Frequency:              types.StringValue("Daily"),
+		IncludedObjectVersions: types.StringValue("All"),
+		OptionalFields:         []types.String{types.StringValue("Size"), types.StringValue("StorageClass")},
+		Prefix:                 types.StringValue("data/"),
+		Destination: s3InventoryDestinationModel{
+			BucketARN:  types.StringValue("arn:aws:s3:::inventory-reports"),
+			AccountID:  types.StringNull(),
+			Format:     types.StringValue("Parquet"),
+			Prefix:     types.StringValue("reports"),
+			Encryption: types.StringValue(s3InventoryEncryptionKMS),
+			KMSKeyID:   types.StringValue("arn:aws:kms:us-east-1:111122223333:key/example"),
+		},
+	}
+
+	if got := flattenS3Inventory(*expandS3Inventory(item)); !reflect.DeepEqual(got, item) {
+		t.Errorf("round trip changed the configuration:\ngot  %+v\nwant %+v", got, item)
+	}
+}
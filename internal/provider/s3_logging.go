@@ -0,0 +1,317 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+const (
+	s3LoggingKeyFormatSimple      = "simple"
+	s3LoggingKeyFormatPartitioned = "partitioned"
+)
+
+// s3LoggingModel is the server access logging configuration of a bucket.
+type s3LoggingModel struct {
+	TargetBucket         types.String          `tfsdk:"target_bucket"`
+	TargetPrefix         types.String          `tfsdk:"target_prefix"`
+	KeyFormat            types.String          `tfsdk:"key_format"`
+	PartitionDateSource  types.String          `tfsdk:"partition_date_source"`
+	TargetGrants         []s3LoggingGrantModel `tfsdk:"target_grants"`
+	AttachDeliveryPolicy types.Bool            `tfsdk:"attach_delivery_policy"`
+}
+
+type s3LoggingGrantModel struct {
+	Type       types.String `tfsdk:"type"`
+	ID         types.String `tfsdk:"id"`
+	Email      types.String `tfsdk:"email"`
+	URI        types.String `tfsdk:"uri"`
+	Permission types.String `tfsdk:"permission"`
+}
+
+func s3LoggingSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional:    true,
+		Description: "Server access logging for the bucket.",
+		Attributes: map[string]schema.Attribute{
+			"target_bucket": schema.StringAttribute{
+				Required:    true,
+				Description: "Bucket that receives the access logs.",
+			},
+			"target_prefix": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				Description: "Prefix for all log object keys.",
+			},
+			"key_format": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(s3LoggingKeyFormatSimple),
+				Description: "Log object key format, either simple or partitioned.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(s3LoggingKeyFormatSimple, s3LoggingKeyFormatPartitioned),
+				},
+			},
+			"partition_date_source": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(string(awstypes.PartitionDateSourceEventTime)),
+				Description: "Date used in partitioned keys, either EventTime or DeliveryTime. Ignored for the simple key format.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(awstypes.PartitionDateSourceEventTime),
+						string(awstypes.PartitionDateSourceDeliveryTime),
+					),
+				},
+			},
+			"target_grants": schema.ListNestedAttribute{
+				Optional:    true,
+				Description: "Grants on the delivered log objects.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Required:    true,
+							Description: "Grantee type: CanonicalUser, AmazonCustomerByEmail or Group.",
+							Validators: []validator.String{
+								stringvalidator.OneOf(
+									string(awstypes.TypeCanonicalUser),
+									string(awstypes.TypeAmazonCustomerByEmail),
+									string(awstypes.TypeGroup),
+								),
+							},
+						},
+						"id": schema.StringAttribute{
+							Optional:    true,
+							Description: "Canonical user ID of a CanonicalUser grantee.",
+						},
+						"email": schema.StringAttribute{
+							Optional:    true,
+							Description: "Email address of an AmazonCustomerByEmail grantee.",
+						},
+						"uri": schema.StringAttribute{
+							Optional:    true,
+							Description: "URI of a Group grantee.",
+						},
+						"permission": schema.StringAttribute{
+							Required:    true,
+							Description: "FULL_CONTROL, READ or WRITE.",
+							Validators: []validator.String{
+								stringvalidator.OneOf(
+									string(awstypes.BucketLogsPermissionFullControl),
+									string(awstypes.BucketLogsPermissionRead),
+									string(awstypes.BucketLogsPermissionWrite),
+								),
+							},
+						},
+					},
+				},
+			},
+			"attach_delivery_policy": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Add a statement to the target bucket policy that lets the S3 logging service write this bucket's logs.",
+			},
+		},
+	}
+}
+
+// putS3BucketLogging enables logging as described by config, or disables it when config is nil.
+func putS3BucketLogging(ctx context.Context, svc *s3.Client, bucket string, config *s3LoggingModel) error {
+	status := &awstypes.BucketLoggingStatus{}
+	if config != nil {
+		enabled := &awstypes.LoggingEnabled{
+			TargetBucket: aws.String(config.TargetBucket.ValueString()),
+			TargetPrefix: aws.String(config.TargetPrefix.ValueString()),
+		}
+		if config.KeyFormat.ValueString() == s3LoggingKeyFormatPartitioned {
+			enabled.TargetObjectKeyFormat = &awstypes.TargetObjectKeyFormat{
+				PartitionedPrefix: &awstypes.PartitionedPrefix{
+					PartitionDateSource: awstypes.PartitionDateSource(config.PartitionDateSource.ValueString()),
+				},
+			}
+		} else {
+			enabled.TargetObjectKeyFormat = &awstypes.TargetObjectKeyFormat{
+				SimplePrefix: &awstypes.SimplePrefix{},
+			}
+		}
+		for _, grant := range config.TargetGrants {
+			enabled.TargetGrants = append(enabled.TargetGrants, awstypes.TargetGrant{
+				Grantee: &awstypes.Grantee{
+					Type:         awstypes.Type(grant.Type.ValueString()),
+					ID:           grant.ID.ValueStringPointer(),
+					EmailAddress: grant.Email.ValueStringPointer(),
+					URI:          grant.URI.ValueStringPointer(),
+				},
+				Permission: awstypes.BucketLogsPermission(grant.Permission.ValueString()),
+			})
+		}
+		status.LoggingEnabled = enabled
+	}
+
+	_, err := svc.PutBucketLogging(ctx, &s3.PutBucketLoggingInput{
+		Bucket:              aws.String(bucket),
+		BucketLoggingStatus: status,
+	})
+	return err
+}
+
+// applyS3BucketLogging moves a bucket from its prior logging configuration to the planned one,
+// keeping the log-delivery statement on the target bucket policy in step.
+func applyS3BucketLogging(ctx context.Context, svc *s3.Client, bucket string, prior *s3LoggingModel, planned *s3LoggingModel) error {
+	if prior == nil && planned == nil {
+		return nil
+	}
+
+	if prior != nil && prior.AttachDeliveryPolicy.ValueBool() {
+		moved := planned == nil || !planned.AttachDeliveryPolicy.ValueBool() || !planned.TargetBucket.Equal(prior.TargetBucket)
+		if moved {
+			if err := detachS3LogDeliveryPolicy(ctx, svc, prior.TargetBucket.ValueString(), bucket); err != nil {
+				return err
+			}
+		}
+	}
+	if planned != nil && planned.AttachDeliveryPolicy.ValueBool() {
+		if err := attachS3LogDeliveryPolicy(ctx, svc, planned.TargetBucket.ValueString(), planned.TargetPrefix.ValueString(), bucket); err != nil {
+			return err
+		}
+	}
+
+	return putS3BucketLogging(ctx, svc, bucket, planned)
+}
+
+// readS3BucketLogging returns the current logging configuration, or nil when logging is off.
+// Settings S3 does not report back, such as attach_delivery_policy, are carried over from prior.
+func readS3BucketLogging(ctx context.Context, svc *s3.Client, bucket string, prior *s3LoggingModel) (*s3LoggingModel, error) {
+	out, err := svc.GetBucketLogging(ctx, &s3.GetBucketLoggingInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if out.LoggingEnabled == nil {
+		return nil, nil
+	}
+
+	enabled := out.LoggingEnabled
+	config := &s3LoggingModel{
+		TargetBucket:         types.StringPointerValue(enabled.TargetBucket),
+		TargetPrefix:         types.StringValue(aws.ToString(enabled.TargetPrefix)),
+		KeyFormat:            types.StringValue(s3LoggingKeyFormatSimple),
+		PartitionDateSource:  types.StringValue(string(awstypes.PartitionDateSourceEventTime)),
+		AttachDeliveryPolicy: types.BoolValue(false),
+	}
+	if prior != nil {
+		config.PartitionDateSource = prior.PartitionDateSource
+		config.AttachDeliveryPolicy = prior.AttachDeliveryPolicy
+	}
+	if enabled.TargetObjectKeyFormat != nil && enabled.TargetObjectKeyFormat.PartitionedPrefix != nil {
+		config.KeyFormat = types.StringValue(s3LoggingKeyFormatPartitioned)
+		config.PartitionDateSource = types.StringValue(string(enabled.TargetObjectKeyFormat.PartitionedPrefix.PartitionDateSource))
+	}
+	for _, grant := range enabled.TargetGrants {
+		if grant.Grantee == nil {
+			continue
+		}
+		config.TargetGrants = append(config.TargetGrants, s3LoggingGrantModel{
+			Type:       types.StringValue(string(grant.Grantee.Type)),
+			ID:         types.StringPointerValue(grant.Grantee.ID),
+			Email:      types.StringPointerValue(grant.Grantee.EmailAddress),
+			URI:        types.StringPointerValue(grant.Grantee.URI),
+			Permission: types.StringValue(string(grant.Permission)),
+		})
+	}
+	return config, nil
+}
+
+// s3LogDeliveryStatementID names the policy statement that lets sourceBucket deliver logs,
+// so it can be found again when logging changes or the bucket is removed. The bucket name is
+// hex encoded because a Sid only allows letters and digits, and distinct buckets such as
+// my-logs and my.logs must not share a statement.
+func s3LogDeliveryStatementID(sourceBucket string) string {
+	return "XsynchcoS3AccessLogs" + hex.EncodeToString([]byte(sourceBucket))
+}
+
+// attachS3LogDeliveryPolicy adds or replaces the statement granting the S3 logging service
+// permission to write sourceBucket's logs under prefix in the target bucket.
+func attachS3LogDeliveryPolicy(ctx context.Context, svc *s3.Client, targetBucket string, prefix string, sourceBucket string) error {
+	region := svc.Options().Region
+	statement := map[string]any{
+		"Sid":       s3LogDeliveryStatementID(sourceBucket),
+		"Effect":    "Allow",
+		"Principal": map[string]any{"Service": "logging.s3.amazonaws.com"},
+		"Action":    "s3:PutObject",
+		"Resource":  s3BucketARN(targetBucket, region) + "/" + prefix + "*",
+		"Condition": map[string]any{
+			"ArnLike": map[string]any{"aws:SourceArn": s3BucketARN(sourceBucket, region)},
+		},
+	}
+	return updateS3BucketPolicyStatement(ctx, svc, targetBucket, s3LogDeliveryStatementID(sourceBucket), statement)
+}
+
+// detachS3LogDeliveryPolicy removes the statement added by attachS3LogDeliveryPolicy.
+func detachS3LogDeliveryPolicy(ctx context.Context, svc *s3.Client, targetBucket string, sourceBucket string) error {
+	return updateS3BucketPolicyStatement(ctx, svc, targetBucket, s3LogDeliveryStatementID(sourceBucket), nil)
+}
+
+// updateS3BucketPolicyStatement replaces the statement with the given Sid in the bucket policy,
+// leaving every other statement untouched. A nil statement removes it, and the policy is
+// deleted once no statements remain.
+func updateS3BucketPolicyStatement(ctx context.Context, svc *s3.Client, bucket string, sid string, statement map[string]any) error {
+	policy := map[string]any{"Version": "2012-10-17"}
+	out, err := svc.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{Bucket: aws.String(bucket)})
+	if err != nil && !isS3ErrorCode(err, "NoSuchBucketPolicy") {
+		return err
+	}
+	if err == nil && out.Policy != nil {
+		if err := json.Unmarshal([]byte(*out.Policy), &policy); err != nil {
+			return err
+		}
+	}
+
+	var statements []any
+	switch existing := policy["Statement"].(type) {
+	case []any:
+		statements = existing
+	case map[string]any:
+		statements = []any{existing}
+	}
+
+	kept := make([]any, 0, len(statements)+1)
+	for _, item := range statements {
+		if current, ok := item.(map[string]any); ok && current["Sid"] == sid {
+			continue
+		}
+		kept = append(kept, item)
+	}
+	if statement != nil {
+		kept = append(kept, statement)
+	}
+
+	if len(kept) == 0 {
+		_, err := svc.DeleteBucketPolicy(ctx, &s3.DeleteBucketPolicyInput{Bucket: aws.String(bucket)})
+		return err
+	}
+
+	policy["Statement"] = kept
+	document, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	_, err = svc.PutBucketPolicy(ctx, &s3.PutBucketPolicyInput{
+		Bucket: aws.String(bucket),
+		Policy: aws.String(string(document)),
+	})
+	return err
+}
@@ -0,0 +1,20 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestS3LogDeliveryStatementIDIsUnique(t *testing.T) {
+	sids := map[string]string{}
+	for _, bucket := range []string{"my-logs", "my.logs", "mylogs"} {
+		sid := s3LogDeliveryStatementID(bucket)
+		if !regexp.MustCompile(`^[A-Za-z0-9]+$`).MatchString(sid) {
+			t.Errorf("Sid %q for %s is not alphanumeric", sid, bucket)
+		}
+		if other, ok := sids[sid]; ok {
+			t.Errorf("buckets %s and %s share Sid %q", other, bucket, sid)
+		}
+		sids[sid] = bucket
+	}
+}
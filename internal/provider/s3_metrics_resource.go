@@ -0,0 +1,310 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &s3MetricsResource{}
+	_ resource.ResourceWithConfigure   = &s3MetricsResource{}
+	_ resource.ResourceWithImportState = &s3MetricsResource{}
+)
+
+type s3MetricsResourceModel struct {
+	ID             types.String     `tfsdk:"id"`
+	Last_Updated   types.String     `tfsdk:"last_updated"`
+	Bucket         types.String     `tfsdk:"bucket"`
+	Configurations []s3MetricsModel `tfsdk:"configurations"`
+}
+
+// s3MetricsModel is one request metrics configuration. Without a filter it covers every
+// object in the bucket.
+type s3MetricsModel struct {
+	ID             types.String      `tfsdk:"id"`
+	Prefix         types.String      `tfsdk:"prefix"`
+	Tags           map[string]string `tfsdk:"tags"`
+	AccessPointARN types.String      `tfsdk:"access_point_arn"`
+}
+
+// NewS3MetricsResource is a helper function to simplify the provider implementation.
+func NewS3MetricsResource() resource.Resource {
+	return &s3MetricsResource{}
+}
+
+// s3MetricsResource owns every metrics configuration of one bucket.
+type s3MetricsResource struct {
+	client *ClientS3
+}
+
+func (r *s3MetricsResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the developer", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *s3MetricsResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_bucket_metrics"
+}
+
+// Schema defines the schema for the resource.
+func (r *s3MetricsResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the CloudWatch request metrics configurations of an S3 bucket. " +
+			"Configurations on the bucket that are not listed here are removed.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"bucket": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"configurations": schema.ListNestedAttribute{
+				Required: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
+							Required:    true,
+							Description: "Name of the configuration, unique within the bucket.",
+						},
+						"prefix": schema.StringAttribute{
+							Optional:    true,
+							Description: "Only count requests for keys starting with this prefix.",
+						},
+						"tags": schema.MapAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+							Description: "Only count requests for objects carrying all of these tags.",
+						},
+						"access_point_arn": schema.StringAttribute{
+							Optional:    true,
+							Description: "Only count requests made through this access point.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *s3MetricsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan s3MetricsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &plan, resp.Diagnostics.AddError)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *s3MetricsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state s3MetricsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	configurations, err := r.configurations().read(ctx, state.Bucket.ValueString(), state.Configurations)
+	if err != nil {
+		if isS3ErrorCode(err, "NoSuchBucket") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading metrics configurations",
+			"Could not list metrics configurations for bucket "+state.Bucket.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.ID = state.Bucket
+	state.Configurations = make([]s3MetricsModel, 0, len(configurations))
+	for _, item := range configurations {
+		state.Configurations = append(state.Configurations, flattenS3Metrics(item))
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *s3MetricsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan s3MetricsResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &plan, resp.Diagnostics.AddError)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *s3MetricsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state s3MetricsResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.configurations().deleteAll(ctx, state.Bucket.ValueString(), state.Configurations, resp.Diagnostics.AddError)
+}
+
+// ImportState imports the metrics configurations of an existing bucket by bucket name.
+func (r *s3MetricsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), req.ID)...)
+}
+
+// write puts every planned configuration and deletes any other configuration on the bucket.
+func (r *s3MetricsResource) write(ctx context.Context, plan *s3MetricsResourceModel, addError func(string, string)) {
+	r.configurations().write(ctx, plan.Bucket.ValueString(), plan.Configurations, addError)
+	plan.ID = plan.Bucket
+	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
+}
+
+// configurations returns the operations on the metrics configurations of a bucket.
+func (r *s3MetricsResource) configurations() s3BucketConfigurationSet[s3MetricsModel, awstypes.MetricsConfiguration] {
+	svc := r.client.S3Client
+	return s3BucketConfigurationSet[s3MetricsModel, awstypes.MetricsConfiguration]{
+		kind:    "metrics",
+		modelID: func(item s3MetricsModel) string { return item.ID.ValueString() },
+		id:      func(config awstypes.MetricsConfiguration) string { return aws.ToString(config.Id) },
+		put: func(ctx context.Context, bucket string, item s3MetricsModel) error {
+			_, err := svc.PutBucketMetricsConfiguration(ctx, &s3.PutBucketMetricsConfigurationInput{
+				Bucket:               aws.String(bucket),
+				Id:                   aws.String(item.ID.ValueString()),
+				MetricsConfiguration: expandS3Metrics(item),
+			})
+			return err
+		},
+		delete: func(ctx context.Context, bucket, id string) error {
+			_, err := svc.DeleteBucketMetricsConfiguration(ctx, &s3.DeleteBucketMetricsConfigurationInput{
+				Bucket: aws.String(bucket),
+				Id:     aws.String(id),
+			})
+			return err
+		},
+		list: func(ctx context.Context, bucket string) ([]awstypes.MetricsConfiguration, error) {
+			return listS3MetricsConfigurations(ctx, svc, bucket)
+		},
+	}
+}
+
+// listS3MetricsConfigurations returns every metrics configuration on the bucket.
+func listS3MetricsConfigurations(ctx context.Context, svc *s3.Client, bucket string) ([]awstypes.MetricsConfiguration, error) {
+	var configurations []awstypes.MetricsConfiguration
+	input := &s3.ListBucketMetricsConfigurationsInput{Bucket: aws.String(bucket)}
+	for {
+		out, err := svc.ListBucketMetricsConfigurations(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		configurations = append(configurations, out.MetricsConfigurationList...)
+		if !aws.ToBool(out.IsTruncated) || out.NextContinuationToken == nil {
+			return configurations, nil
+		}
+		input.ContinuationToken = out.NextContinuationToken
+	}
+}
+
+func expandS3Metrics(item s3MetricsModel) *awstypes.MetricsConfiguration {
+	config := &awstypes.MetricsConfiguration{
+		Id: aws.String(item.ID.ValueString()),
+	}
+
+	tags := s3TagsFromMap(item.Tags)
+	conditions := len(tags)
+	if !item.Prefix.IsNull() {
+		conditions++
+	}
+	if !item.AccessPointARN.IsNull() {
+		conditions++
+	}
+	switch {
+	case conditions > 1:
+		config.Filter = &awstypes.MetricsFilterMemberAnd{
+			Value: awstypes.MetricsAndOperator{
+				Prefix:         item.Prefix.ValueStringPointer(),
+				Tags:           tags,
+				AccessPointArn: item.AccessPointARN.ValueStringPointer(),
+			},
+		}
+	case len(tags) == 1:
+		config.Filter = &awstypes.MetricsFilterMemberTag{Value: tags[0]}
+	case !item.Prefix.IsNull():
+		config.Filter = &awstypes.MetricsFilterMemberPrefix{Value: item.Prefix.ValueString()}
+	case !item.AccessPointARN.IsNull():
+		config.Filter = &awstypes.MetricsFilterMemberAccessPointArn{Value: item.AccessPointARN.ValueString()}
+	}
+	return config
+}
+
+func flattenS3Metrics(config awstypes.MetricsConfiguration) s3MetricsModel {
+	item := s3MetricsModel{
+		ID:             types.StringPointerValue(config.Id),
+		Prefix:         types.StringNull(),
+		AccessPointARN: types.StringNull(),
+	}
+	switch filter := config.Filter.(type) {
+	case *awstypes.MetricsFilterMemberAnd:
+		item.Prefix = types.StringPointerValue(filter.Value.Prefix)
+		item.Tags = s3TagsToMap(filter.Value.Tags)
+		item.AccessPointARN = types.StringPointerValue(filter.Value.AccessPointArn)
+	case *awstypes.MetricsFilterMemberTag:
+		item.Tags = s3TagsToMap([]awstypes.Tag{filter.Value})
+	case *awstypes.MetricsFilterMemberPrefix:
+		item.Prefix = types.StringValue(filter.Value)
+	case *awstypes.MetricsFilterMemberAccessPointArn:
+		item.AccessPointARN = types.StringValue(filter.Value)
+	}
+	return item
+}
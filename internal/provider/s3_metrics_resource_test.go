@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestS3MetricsFilterRoundTrip(t *testing.T) {
+	accessPoint := "arn:aws:s3:us-east-1:111122223333:accesspoint/app"
+	cases := map[string]struct {
+		item       s3MetricsModel
+		wantFilter awstypes.MetricsFilter
+	}{
+		"whole bucket": {
+			item:       s3MetricsModel{ID: types.StringValue("all"), Prefix: types.StringNull(), AccessPointARN: types.StringNull()},
+			wantFilter: nil,
+		},
+		"prefix only": {
+			item:       s3MetricsModel{ID: types.StringValue("logs"), Prefix: types.StringValue("logs/"), AccessPointARN: types.StringNull()},
+			wantFilter: &awstypes.MetricsFilterMemberPrefix{},
+		},
+		"access point only": {
+			item:       s3MetricsModel{ID: types.StringValue("app"), Prefix: types.StringNull(), AccessPointARN: types.StringValue(accessPoint)},
+			wantFilter: &awstypes.MetricsFilterMemberAccessPointArn{},
+		},
+		"prefix and tag": {
+			item: s3MetricsModel{
+				ID:             types.StringValue("hot"),
+				Prefix:         types.StringValue("data/"),
+				Tags:           map[string]string{"tier": "hot"},
+				AccessPointARN: types.StringNull(),
+			},
+			wantFilter: &awstypes.MetricsFilterMemberAnd{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			config := expandS3Metrics(tc.item)
+			if reflect.TypeOf(config.Filter) != reflect.TypeOf(tc.wantFilter) {
+				t.Fatalf("filter type = %T, want %T", config.Filter, tc.wantFilter)
+			}
+			if got := flattenS3Metrics(*config); !reflect.DeepEqual(got, tc.item) {
+				t.Errorf("round trip changed the configuration:\ngot  %+v\nwant %+v", got, tc.item)
+			}
+		})
+	}
+}
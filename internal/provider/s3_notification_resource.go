@@ -0,0 +1,434 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &s3NotificationResource{}
+	_ resource.ResourceWithConfigure   = &s3NotificationResource{}
+	_ resource.ResourceWithImportState = &s3NotificationResource{}
+)
+
+type s3NotificationResourceModel struct {
+	ID              types.String                `tfsdk:"id"`
+	Last_Updated    types.String                `tfsdk:"last_updated"`
+	Bucket          types.String                `tfsdk:"bucket"`
+	EventBridge     types.Bool                  `tfsdk:"eventbridge"`
+	Queues          []s3NotificationTargetModel `tfsdk:"queues"`
+	Topics          []s3NotificationTargetModel `tfsdk:"topics"`
+	LambdaFunctions []s3NotificationTargetModel `tfsdk:"lambda_functions"`
+}
+
+// s3NotificationTargetModel is one queue, topic or function subscribed to bucket events.
+type s3NotificationTargetModel struct {
+	ID           types.String   `tfsdk:"id"`
+	ARN          types.String   `tfsdk:"arn"`
+	Events       []types.String `tfsdk:"events"`
+	FilterPrefix types.String   `tfsdk:"filter_prefix"`
+	FilterSuffix types.String   `tfsdk:"filter_suffix"`
+}
+
+// NewS3NotificationResource is a helper function to simplify the provider implementation.
+func NewS3NotificationResource() resource.Resource {
+	return &s3NotificationResource{}
+}
+
+// s3NotificationResource owns the complete notification configuration of one bucket.
+type s3NotificationResource struct {
+	client *ClientS3
+}
+
+func (r *s3NotificationResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the developer", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *s3NotificationResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_bucket_notification"
+}
+
+func s3NotificationTargetSchema(arnDescription string) schema.ListNestedAttribute {
+	return schema.ListNestedAttribute{
+		Optional: true,
+		NestedObject: schema.NestedAttributeObject{
+			Attributes: map[string]schema.Attribute{
+				"id": schema.StringAttribute{
+					Optional:    true,
+					Computed:    true,
+					Description: "Unique ID of the notification. Generated by S3 when not set.",
+					PlanModifiers: []planmodifier.String{
+						stringplanmodifier.UseStateForUnknown(),
+					},
+				},
+				"arn": schema.StringAttribute{
+					Required:    true,
+					Description: arnDescription,
+				},
+				"events": schema.SetAttribute{
+					Required:    true,
+					ElementType: types.StringType,
+					Description: "S3 event types to deliver, for example s3:ObjectCreated:*.",
+				},
+				"filter_prefix": schema.StringAttribute{
+					Optional:    true,
+					Description: "Only deliver events for keys starting with this prefix.",
+				},
+				"filter_suffix": schema.StringAttribute{
+					Optional:    true,
+					Description: "Only deliver events for keys ending with this suffix.",
+				},
+			},
+		},
+	}
+}
+
+// Schema defines the schema for the resource.
+func (r *s3NotificationResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the complete event notification configuration of an S3 bucket. " +
+			"S3 replaces the whole configuration on every write, so use only one of these per bucket.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"bucket": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"eventbridge": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Send all bucket events to Amazon EventBridge.",
+			},
+			"queues":           s3NotificationTargetSchema("ARN of the SQS queue."),
+			"topics":           s3NotificationTargetSchema("ARN of the SNS topic."),
+			"lambda_functions": s3NotificationTargetSchema("ARN of the Lambda function."),
+		},
+	}
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *s3NotificationResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan s3NotificationResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &plan, resp.Diagnostics.AddError)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *s3NotificationResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state s3NotificationResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	out, err := r.client.S3Client.GetBucketNotificationConfiguration(ctx, &s3.GetBucketNotificationConfigurationInput{
+		Bucket: aws.String(state.Bucket.ValueString()),
+	})
+	if err != nil {
+		if isS3ErrorCode(err, "NoSuchBucket") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading bucket notifications",
+			"Could not read notification configuration for bucket "+state.Bucket.ValueString()+": "+err.Error(),
+		)
+		return
+	}
+
+	state.ID = state.Bucket
+	state.EventBridge = types.BoolValue(out.EventBridgeConfiguration != nil)
+	state.Queues = nil
+	for _, item := range out.QueueConfigurations {
+		state.Queues = append(state.Queues, flattenS3NotificationTarget(item.Id, item.QueueArn, item.Events, item.Filter))
+	}
+	state.Topics = nil
+	for _, item := range out.TopicConfigurations {
+		state.Topics = append(state.Topics, flattenS3NotificationTarget(item.Id, item.TopicArn, item.Events, item.Filter))
+	}
+	state.LambdaFunctions = nil
+	for _, item := range out.LambdaFunctionConfigurations {
+		state.LambdaFunctions = append(state.LambdaFunctions, flattenS3NotificationTarget(item.Id, item.LambdaFunctionArn, item.Events, item.Filter))
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *s3NotificationResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan s3NotificationResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.write(ctx, &plan, resp.Diagnostics.AddError)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *s3NotificationResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state s3NotificationResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// An empty configuration removes every notification from the bucket.
+	_, err := r.client.S3Client.PutBucketNotificationConfiguration(ctx, &s3.PutBucketNotificationConfigurationInput{
+		Bucket:                    aws.String(state.Bucket.ValueString()),
+		NotificationConfiguration: &awstypes.NotificationConfiguration{},
+	})
+	if err != nil && !isS3ErrorCode(err, "NoSuchBucket") {
+		resp.Diagnostics.AddError(
+			"Error deleting bucket notifications",
+			"Could not clear notification configuration for bucket "+state.Bucket.ValueString()+": "+err.Error(),
+		)
+	}
+}
+
+// ImportState imports the notification configuration of an existing bucket by bucket name.
+func (r *s3NotificationResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), req.ID)...)
+}
+
+// write replaces the bucket's notification configuration with the plan and then reads it
+// back, so IDs that S3 generated end up in state.
+func (r *s3NotificationResource) write(ctx context.Context, plan *s3NotificationResourceModel, addError func(string, string)) {
+	bucket := plan.Bucket.ValueString()
+	config := &awstypes.NotificationConfiguration{}
+	if plan.EventBridge.ValueBool() {
+		config.EventBridgeConfiguration = &awstypes.EventBridgeConfiguration{}
+	}
+	for _, item := range plan.Queues {
+		id, events, filter := expandS3NotificationTarget(item)
+		config.QueueConfigurations = append(config.QueueConfigurations, awstypes.QueueConfiguration{
+			Id: id, QueueArn: aws.String(item.ARN.ValueString()), Events: events, Filter: filter,
+		})
+	}
+	for _, item := range plan.Topics {
+		id, events, filter := expandS3NotificationTarget(item)
+		config.TopicConfigurations = append(config.TopicConfigurations, awstypes.TopicConfiguration{
+			Id: id, TopicArn: aws.String(item.ARN.ValueString()), Events: events, Filter: filter,
+		})
+	}
+	for _, item := range plan.LambdaFunctions {
+		id, events, filter := expandS3NotificationTarget(item)
+		config.LambdaFunctionConfigurations = append(config.LambdaFunctionConfigurations, awstypes.LambdaFunctionConfiguration{
+			Id: id, LambdaFunctionArn: aws.String(item.ARN.ValueString()), Events: events, Filter: filter,
+		})
+	}
+
+	_, err := r.client.S3Client.PutBucketNotificationConfiguration(ctx, &s3.PutBucketNotificationConfigurationInput{
+		Bucket:                    aws.String(bucket),
+		NotificationConfiguration: config,
+	})
+	if err != nil {
+		addError(
+			"Error configuring bucket notifications",
+			"Could not write notification configuration for bucket "+bucket+": "+err.Error(),
+		)
+		return
+	}
+
+	out, err := r.client.S3Client.GetBucketNotificationConfiguration(ctx, &s3.GetBucketNotificationConfigurationInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		addError(
+			"Error reading bucket notifications",
+			"Could not read back notification configuration for bucket "+bucket+": "+err.Error(),
+		)
+		return
+	}
+	var remoteQueues, remoteTopics, remoteFunctions []s3NotificationTargetModel
+	for _, item := range out.QueueConfigurations {
+		remoteQueues = append(remoteQueues, flattenS3NotificationTarget(item.Id, item.QueueArn, item.Events, item.Filter))
+	}
+	for _, item := range out.TopicConfigurations {
+		remoteTopics = append(remoteTopics, flattenS3NotificationTarget(item.Id, item.TopicArn, item.Events, item.Filter))
+	}
+	for _, item := range out.LambdaFunctionConfigurations {
+		remoteFunctions = append(remoteFunctions, flattenS3NotificationTarget(item.Id, item.LambdaFunctionArn, item.Events, item.Filter))
+	}
+	for _, targets := range []struct {
+		planned, remote []s3NotificationTargetModel
+	}{
+		{plan.Queues, remoteQueues},
+		{plan.Topics, remoteTopics},
+		{plan.LambdaFunctions, remoteFunctions},
+	} {
+		if err := matchS3NotificationIDs(targets.planned, targets.remote); err != nil {
+			addError(
+				"Error reading bucket notifications",
+				"Could not read back notification configuration for bucket "+bucket+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	plan.ID = plan.Bucket
+	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
+}
+
+// matchS3NotificationIDs copies the IDs S3 generated onto the planned targets that did not
+// set one. S3 does not document the order it lists notifications in, so targets are matched
+// on their ARN, events and filter rather than by position, and each remote target is used
+// at most once.
+func matchS3NotificationIDs(planned, remote []s3NotificationTargetModel) error {
+	used := make([]bool, len(remote))
+	for _, item := range planned {
+		if item.ID.IsNull() || item.ID.IsUnknown() {
+			continue
+		}
+		for index, candidate := range remote {
+			if candidate.ID.Equal(item.ID) {
+				used[index] = true
+			}
+		}
+	}
+	for index := range planned {
+		item := &planned[index]
+		if !item.ID.IsNull() && !item.ID.IsUnknown() {
+			continue
+		}
+		key := s3NotificationTargetKey(*item)
+		matched := false
+		for candidateIndex, candidate := range remote {
+			if !used[candidateIndex] && s3NotificationTargetKey(candidate) == key {
+				item.ID = candidate.ID
+				used[candidateIndex] = true
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return fmt.Errorf("no notification for %s was returned", item.ARN.ValueString())
+		}
+	}
+	return nil
+}
+
+// s3NotificationTargetKey identifies a target by everything but its ID. Events are a set,
+// so they are sorted first.
+func s3NotificationTargetKey(item s3NotificationTargetModel) string {
+	events := make([]string, 0, len(item.Events))
+	for _, event := range item.Events {
+		events = append(events, event.ValueString())
+	}
+	sort.Strings(events)
+	return strings.Join([]string{
+		item.ARN.ValueString(),
+		strings.Join(events, ","),
+		item.FilterPrefix.ValueString(),
+		item.FilterSuffix.ValueString(),
+	}, "\x00")
+}
+
+func expandS3NotificationTarget(item s3NotificationTargetModel) (*string, []awstypes.Event, *awstypes.NotificationConfigurationFilter) {
+	var id *string
+	if !item.ID.IsNull() && !item.ID.IsUnknown() {
+		id = aws.String(item.ID.ValueString())
+	}
+
+	events := make([]awstypes.Event, 0, len(item.Events))
+	for _, event := range item.Events {
+		events = append(events, awstypes.Event(event.ValueString()))
+	}
+
+	var rules []awstypes.FilterRule
+	if !item.FilterPrefix.IsNull() {
+		rules = append(rules, awstypes.FilterRule{Name: awstypes.FilterRuleNamePrefix, Value: aws.String(item.FilterPrefix.ValueString())})
+	}
+	if !item.FilterSuffix.IsNull() {
+		rules = append(rules, awstypes.FilterRule{Name: awstypes.FilterRuleNameSuffix, Value: aws.String(item.FilterSuffix.ValueString())})
+	}
+	var filter *awstypes.NotificationConfigurationFilter
+	if len(rules) > 0 {
+		filter = &awstypes.NotificationConfigurationFilter{Key: &awstypes.S3KeyFilter{FilterRules: rules}}
+	}
+	return id, events, filter
+}
+
+func flattenS3NotificationTarget(id *string, arn *string, events []awstypes.Event, filter *awstypes.NotificationConfigurationFilter) s3NotificationTargetModel {
+	target := s3NotificationTargetModel{
+		ID:           types.StringPointerValue(id),
+		ARN:          types.StringPointerValue(arn),
+		FilterPrefix: types.StringNull(),
+		FilterSuffix: types.StringNull(),
+	}
+	for _, event := range events {
+		target.Events = append(target.Events, types.StringValue(string(event)))
+	}
+	if filter != nil && filter.Key != nil {
+		for _, rule := range filter.Key.FilterRules {
+			// S3 reports rule names as "Prefix"/"Suffix" even though they are sent lowercase.
+			switch {
+			case strings.EqualFold(string(rule.Name), string(awstypes.FilterRuleNamePrefix)):
+				target.FilterPrefix = types.StringPointerValue(rule.Value)
+			case strings.EqualFold(string(rule.Name), string(awstypes.FilterRuleNameSuffix)):
+				target.FilterSuffix = types.StringPointerValue(rule.Value)
+			}
+		}
+	}
+	return target
+}
@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestS3NotificationTargetRoundTrip(t *testing.T) {
+	cases := map[string]s3NotificationTargetModel{
+		"no filter": {
+			ID:           types.StringValue("created"),
+			ARN:          types.StringValue("arn:aws:sqs:eu-west-1:111122223333:events"),
+			Events:       []types.String{types.StringValue("s3:ObjectCreated:*")},
+			FilterPrefix: types.StringNull(),
+			FilterSuffix: types.StringNull(),
+		},
+		"prefix and suffix": {
+			ID:  types.StringValue("images"),
+			ARN: types.StringValue("arn:aws:lambda:eu-west-1:111122223333:function:thumbnail"),
+			Events: []types.String{
+				types.StringValue("s3:ObjectCreated:Put"),
+				types.StringValue("s3:ObjectRemoved:*"),
+			},
+			FilterPrefix: types.StringValue("uploads/"),
+			FilterSuffix: types.StringValue(".jpg"),
+		},
+	}
+	for name, item := range cases {
+		t.Run(name, func(t *testing.T) {
+			id, events, filter := expandS3NotificationTarget(item)
+			if got := flattenS3NotificationTarget(id, item.ARN.ValueStringPointer(), events, filter); !reflect.DeepEqual(got, item) {
+				t.Errorf("round trip changed the target:\ngot  %+v\nwant %+v", got, item)
+			}
+		})
+	}
+}
+
+func TestFlattenS3NotificationTargetRuleNames(t *testing.T) {
+	// S3 reports the filter rule names capitalised.
+	target := flattenS3NotificationTarget(nil, nil, nil, &awstypes.NotificationConfigurationFilter{
+		Key: &awstypes.S3KeyFilter{FilterRules: []awstypes.FilterRule{
+			{Name: "Prefix", Value: aws.String("logs/")},
+			{Name: "Suffix", Value: aws.String(".gz")},
+		}},
+	})
+	if target.FilterPrefix.ValueString() != "logs/" || target.FilterSuffix.ValueString() != ".gz" {
+		t.Errorf("filter = %v %v", target.FilterPrefix, target.FilterSuffix)
+	}
+}
+
+func TestMatchS3NotificationIDs(t *testing.T) {
+	target := func(id types.String, arn, prefix string, events ...string) s3NotificationTargetModel {
+		item := s3NotificationTargetModel{ID: id, ARN: types.StringValue(arn), FilterPrefix: types.StringValue(prefix), FilterSuffix: types.StringNull()}
+		for _, event := range events {
+			item.Events = append(item.Events, types.StringValue(event))
+		}
+		return item
+	}
+	const queue = "arn:aws:sqs:eu-west-1:111122223333:events"
+
+	planned := []s3NotificationTargetModel{
+		target(types.StringUnknown(), queue, "a/", "s3:ObjectCreated:*", "s3:ObjectRemoved:*"),
+		target(types.StringValue("named"), queue, "b/", "s3:ObjectCreated:*"),
+		target(types.StringUnknown(), queue, "c/", "s3:ObjectCreated:*"),
+	}
+	// S3 returns the targets in a different order and the events of a set in any order.
+	remote := []s3NotificationTargetModel{
+		target(types.StringValue("generated-c"), queue, "c/", "s3:ObjectCreated:*"),
+		target(types.StringValue("named"), queue, "b/", "s3:ObjectCreated:*"),
+		target(types.StringValue("generated-a"), queue, "a/", "s3:ObjectRemoved:*", "s3:ObjectCreated:*"),
+	}
+	if err := matchS3NotificationIDs(planned, remote); err != nil {
+		t.Fatal(err)
+	}
+	for index, want := range []string{"generated-a", "named", "generated-c"} {
+		if got := planned[index].ID.ValueString(); got != want {
+			t.Errorf("target %d has ID %s, want %s", index, got, want)
+		}
+	}
+
+	missing := []s3NotificationTargetModel{target(types.StringUnknown(), queue, "d/", "s3:ObjectCreated:*")}
+	if err := matchS3NotificationIDs(missing, remote); err == nil {
+		t.Error("expected an error for a target S3 did not return")
+	}
+}
@@ -0,0 +1,325 @@
+package provider
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &s3ObjectDataSource{}
+	_ datasource.DataSourceWithConfigure = &s3ObjectDataSource{}
+)
+
+const (
+	s3BodyFormatAuto   = "auto"
+	s3BodyFormatText   = "text"
+	s3BodyFormatBase64 = "base64"
+	s3BodyFormatNone   = "none"
+
+	s3DefaultMaxBodySize = 1024 * 1024
+)
+
+// NewS3ObjectDataSource is a helper function to simplify the provider implementation.
+func NewS3ObjectDataSource() datasource.DataSource {
+	return &s3ObjectDataSource{}
+}
+
+// s3ObjectDataSource reads the metadata and, optionally, the content of one object.
+type s3ObjectDataSource struct {
+	client *ClientS3
+}
+
+type s3ObjectDataSourceModel struct {
+	Bucket               types.String `tfsdk:"bucket"`
+	Key                  types.String `tfsdk:"key"`
+	VersionID            types.String `tfsdk:"version_id"`
+	Range                types.String `tfsdk:"range"`
+	SSECustomerKey       types.String `tfsdk:"sse_customer_key"`
+	BodyFormat           types.String `tfsdk:"body_format"`
+	MaxBodySize          types.Int64  `tfsdk:"max_body_size"`
+	ChecksumMode         types.Bool   `tfsdk:"checksum_mode"`
+	Body                 types.String `tfsdk:"body"`
+	BodyBase64           types.String `tfsdk:"body_base64"`
+	ContentType          types.String `tfsdk:"content_type"`
+	ContentLength        types.Int64  `tfsdk:"content_length"`
+	ContentEncoding      types.String `tfsdk:"content_encoding"`
+	ContentDisposition   types.String `tfsdk:"content_disposition"`
+	CacheControl         types.String `tfsdk:"cache_control"`
+	ETag                 types.String `tfsdk:"etag"`
+	LastModified         types.String `tfsdk:"last_modified"`
+	StorageClass         types.String `tfsdk:"storage_class"`
+	ServerSideEncryption types.String `tfsdk:"server_side_encryption"`
+	KMSKeyID             types.String `tfsdk:"kms_key_id"`
+	Metadata             types.Map    `tfsdk:"metadata"`
+	ChecksumCRC32        types.String `tfsdk:"checksum_crc32"`
+	ChecksumCRC32C       types.String `tfsdk:"checksum_crc32c"`
+	ChecksumCRC64NVME    types.String `tfsdk:"checksum_crc64nvme"`
+	ChecksumSHA1         types.String `tfsdk:"checksum_sha1"`
+	ChecksumSHA256       types.String `tfsdk:"checksum_sha256"`
+}
+
+// Metadata returns the data source type name.
+func (d *s3ObjectDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_object"
+}
+
+// Schema defines the schema for the data source.
+func (d *s3ObjectDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	computedString := func(description string) schema.StringAttribute {
+		return schema.StringAttribute{Computed: true, Description: description}
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Reads the metadata of an S3 object and, for textual content or on request, its body.",
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required: true,
+			},
+			"key": schema.StringAttribute{
+				Required: true,
+			},
+			"version_id": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Version to read. Defaults to the current version.",
+			},
+			"range": schema.StringAttribute{
+				Optional:    true,
+				Description: "Byte range to read, for example \"bytes=0-1023\".",
+			},
+			"sse_customer_key": schema.StringAttribute{
+				Optional:    true,
+				Sensitive:   true,
+				Description: "Base64-encoded 256-bit key for objects encrypted with SSE-C.",
+			},
+			"body_format": schema.StringAttribute{
+				Optional: true,
+				Description: "How to return the body: auto (text for textual content types, otherwise none), " +
+					"text, base64 or none. Defaults to auto.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(s3BodyFormatAuto, s3BodyFormatText, s3BodyFormatBase64, s3BodyFormatNone),
+				},
+			},
+			"max_body_size": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Largest body, in bytes, that is returned. Defaults to 1 MiB.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"checksum_mode": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Request the object's stored checksums and verify the body against them. The whole object is downloaded to verify it, even when body_format is none or the object is larger than max_body_size.",
+			},
+			"body":                   computedString("Object content as UTF-8 text."),
+			"body_base64":            computedString("Object content as base64."),
+			"content_type":           computedString(""),
+			"content_encoding":       computedString(""),
+			"content_disposition":    computedString(""),
+			"cache_control":          computedString(""),
+			"etag":                   computedString(""),
+			"last_modified":          computedString("RFC 3339 timestamp of the last modification."),
+			"storage_class":          computedString(""),
+			"server_side_encryption": computedString(""),
+			"kms_key_id":             computedString(""),
+			"checksum_crc32":         computedString("Only set when checksum_mode is true and the object has this checksum."),
+			"checksum_crc32c":        computedString("Only set when checksum_mode is true and the object has this checksum."),
+			"checksum_crc64nvme":     computedString("Only set when checksum_mode is true and the object has this checksum."),
+			"checksum_sha1":          computedString("Only set when checksum_mode is true and the object has this checksum."),
+			"checksum_sha256":        computedString("Only set when checksum_mode is true and the object has this checksum."),
+			"content_length": schema.Int64Attribute{
+				Computed: true,
+			},
+			"metadata": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *s3ObjectDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state s3ObjectDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	bucket, key := state.Bucket.ValueString(), state.Key.ValueString()
+	input := &s3.GetObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: state.VersionID.ValueStringPointer(),
+		Range:     state.Range.ValueStringPointer(),
+	}
+	if !state.SSECustomerKey.IsNull() {
+		raw, err := base64.StdEncoding.DecodeString(state.SSECustomerKey.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("sse_customer_key"), "Invalid SSE-C Key", "The key must be base64-encoded: "+err.Error())
+			return
+		}
+		digest := md5.Sum(raw)
+		input.SSECustomerAlgorithm = aws.String(string(awstypes.ServerSideEncryptionAes256))
+		input.SSECustomerKey = state.SSECustomerKey.ValueStringPointer()
+		input.SSECustomerKeyMD5 = aws.String(base64.StdEncoding.EncodeToString(digest[:]))
+	}
+	if state.ChecksumMode.ValueBool() {
+		input.ChecksumMode = awstypes.ChecksumModeEnabled
+	}
+
+	out, err := d.client.S3Client.GetObject(ctx, input)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"unable to read object",
+			"Could not read s3://"+bucket+"/"+key+": "+err.Error(),
+		)
+		return
+	}
+	defer out.Body.Close()
+
+	state.VersionID = types.StringValue(aws.ToString(out.VersionId))
+	state.ContentType = types.StringValue(aws.ToString(out.ContentType))
+	state.ContentLength = types.Int64Value(aws.ToInt64(out.ContentLength))
+	state.ContentEncoding = types.StringValue(aws.ToString(out.ContentEncoding))
+	state.ContentDisposition = types.StringValue(aws.ToString(out.ContentDisposition))
+	state.CacheControl = types.StringValue(aws.ToString(out.CacheControl))
+	state.ETag = types.StringValue(aws.ToString(out.ETag))
+	state.LastModified = types.StringValue(aws.ToTime(out.LastModified).Format(time.RFC3339))
+	state.StorageClass = types.StringValue(string(awstypes.StorageClassStandard))
+	if out.StorageClass != "" {
+		state.StorageClass = types.StringValue(string(out.StorageClass))
+	}
+	state.ServerSideEncryption = types.StringValue(string(out.ServerSideEncryption))
+	state.KMSKeyID = types.StringValue(aws.ToString(out.SSEKMSKeyId))
+	state.ChecksumCRC32 = types.StringNull()
+	state.ChecksumCRC32C = types.StringNull()
+	state.ChecksumCRC64NVME = types.StringNull()
+	state.ChecksumSHA1 = types.StringNull()
+	state.ChecksumSHA256 = types.StringNull()
+	if state.ChecksumMode.ValueBool() {
+		state.ChecksumCRC32 = types.StringPointerValue(out.ChecksumCRC32)
+		state.ChecksumCRC32C = types.StringPointerValue(out.ChecksumCRC32C)
+		state.ChecksumCRC64NVME = types.StringPointerValue(out.ChecksumCRC64NVME)
+		state.ChecksumSHA1 = types.StringPointerValue(out.ChecksumSHA1)
+		state.ChecksumSHA256 = types.StringPointerValue(out.ChecksumSHA256)
+	}
+	metadata, diags := types.MapValueFrom(ctx, types.StringType, out.Metadata)
+	resp.Diagnostics.Append(diags...)
+	state.Metadata = metadata
+	state.Body = types.StringNull()
+	state.BodyBase64 = types.StringNull()
+
+	format := state.BodyFormat.ValueString()
+	if format == "" || format == s3BodyFormatAuto {
+		format = s3BodyFormatNone
+		if isTextualContentType(aws.ToString(out.ContentType)) {
+			format = s3BodyFormatText
+		}
+	}
+	if format != s3BodyFormatNone {
+		maxSize := state.MaxBodySize.ValueInt64()
+		if state.MaxBodySize.IsNull() {
+			maxSize = s3DefaultMaxBodySize
+		}
+		// Read one byte past the cap so an oversized body is detected without loading it.
+		body, err := io.ReadAll(io.LimitReader(out.Body, maxSize+1))
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"unable to read object body",
+				"Could not read the body of s3://"+bucket+"/"+key+": "+err.Error(),
+			)
+			return
+		}
+		switch {
+		case int64(len(body)) > maxSize:
+			resp.Diagnostics.AddAttributeWarning(
+				path.Root("max_body_size"),
+				"Object Body Not Returned",
+				fmt.Sprintf("s3://%s/%s is larger than max_body_size (%d bytes), so body and body_base64 are left empty.", bucket, key, maxSize),
+			)
+		case format == s3BodyFormatBase64:
+			state.BodyBase64 = types.StringValue(base64.StdEncoding.EncodeToString(body))
+		case !utf8.Valid(body):
+			resp.Diagnostics.AddAttributeError(
+				path.Root("body_format"),
+				"Object Body Is Not Text",
+				fmt.Sprintf("s3://%s/%s is not valid UTF-8. Set body_format = \"base64\" to read binary content.", bucket, key),
+			)
+			return
+		default:
+			state.Body = types.StringValue(string(body))
+		}
+	}
+	if state.ChecksumMode.ValueBool() {
+		// The SDK compares the checksum once the body has been read to the end.
+		if _, err := io.Copy(io.Discard, out.Body); err != nil {
+			resp.Diagnostics.AddError(
+				"unable to verify object checksum",
+				"Could not verify the body of s3://"+bucket+"/"+key+": "+err.Error(),
+			)
+			return
+		}
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// isTextualContentType reports whether a body with this content type can safely be returned
+// as a string.
+func isTextualContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "+xml") {
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml", "application/javascript", "application/ecmascript",
+		"application/x-yaml", "application/yaml", "application/toml", "application/x-sh",
+		"application/hcl", "application/x-www-form-urlencoded":
+		return true
+	}
+	return false
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *s3ObjectDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
@@ -0,0 +1,21 @@
+package provider
+
+import "testing"
+
+func TestIsTextualContentType(t *testing.T) {
+	cases := map[string]bool{
+		"text/plain; charset=utf-8": true,
+		"application/json":          true,
+		"application/ld+json":       true,
+		"image/svg+xml":             true,
+		"application/x-yaml":        true,
+		"application/octet-stream":  false,
+		"image/png":                 false,
+		"":                          false,
+	}
+	for contentType, want := range cases {
+		if got := isTextualContentType(contentType); got != want {
+			t.Errorf("isTextualContentType(%q) = %v, want %v", contentType, got, want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// s3ObjectLockModel is the default retention rule applied to new object versions.
+type s3ObjectLockModel struct {
+	Mode  types.String `tfsdk:"mode"`
+	Days  types.Int64  `tfsdk:"days"`
+	Years types.Int64  `tfsdk:"years"`
+}
+
+func s3ObjectLockSchema() schema.SingleNestedAttribute {
+	return schema.SingleNestedAttribute{
+		Optional:    true,
+		Description: "Default retention rule for new objects. Requires object_lock_enabled.",
+		Attributes: map[string]schema.Attribute{
+			"mode": schema.StringAttribute{
+				Required:    true,
+				Description: "Retention mode, either GOVERNANCE or COMPLIANCE.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						string(awstypes.ObjectLockRetentionModeGovernance),
+						string(awstypes.ObjectLockRetentionModeCompliance),
+					),
+				},
+			},
+			"days": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of days new objects are retained. Conflicts with years.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+					int64validator.ExactlyOneOf(path.MatchRelative().AtParent().AtName("years")),
+				},
+			},
+			"years": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of years new objects are retained. Conflicts with days.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+// putS3ObjectLockConfiguration sets the default retention rule on a bucket that was created
+// with Object Lock enabled. A nil lock clears the default retention.
+func putS3ObjectLockConfiguration(ctx context.Context, svc *s3.Client, bucket string, lock *s3ObjectLockModel) error {
+	config := &awstypes.ObjectLockConfiguration{
+		ObjectLockEnabled: awstypes.ObjectLockEnabledEnabled,
+	}
+	if lock != nil {
+		retention := &awstypes.DefaultRetention{
+			Mode: awstypes.ObjectLockRetentionMode(lock.Mode.ValueString()),
+		}
+		if !lock.Days.IsNull() {
+			retention.Days = aws.Int32(int32(lock.Days.ValueInt64()))
+		}
+		if !lock.Years.IsNull() {
+			retention.Years = aws.Int32(int32(lock.Years.ValueInt64()))
+		}
+		config.Rule = &awstypes.ObjectLockRule{DefaultRetention: retention}
+	}
+
+	_, err := svc.PutObjectLockConfiguration(ctx, &s3.PutObjectLockConfigurationInput{
+		Bucket:                  aws.String(bucket),
+		ObjectLockConfiguration: config,
+	})
+	return err
+}
+
+// readS3ObjectLockConfiguration reports whether Object Lock is enabled on the bucket and
+// returns its default retention rule, if any.
+func readS3ObjectLockConfiguration(ctx context.Context, svc *s3.Client, bucket string) (bool, *s3ObjectLockModel, error) {
+	out, err := svc.GetObjectLockConfiguration(ctx, &s3.GetObjectLockConfigurationInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if isS3ErrorCode(err, "ObjectLockConfigurationNotFoundError") {
+			return false, nil, nil
+		}
+		return false, nil, err
+	}
+	if out.ObjectLockConfiguration == nil || out.ObjectLockConfiguration.ObjectLockEnabled != awstypes.ObjectLockEnabledEnabled {
+		return false, nil, nil
+	}
+
+	rule := out.ObjectLockConfiguration.Rule
+	if rule == nil || rule.DefaultRetention == nil {
+		return true, nil, nil
+	}
+	lock := &s3ObjectLockModel{
+		Mode:  types.StringValue(string(rule.DefaultRetention.Mode)),
+		Days:  types.Int64Null(),
+		Years: types.Int64Null(),
+	}
+	if rule.DefaultRetention.Days != nil {
+		lock.Days = types.Int64Value(int64(*rule.DefaultRetention.Days))
+	}
+	if rule.DefaultRetention.Years != nil {
+		lock.Years = types.Int64Value(int64(*rule.DefaultRetention.Years))
+	}
+	return true, lock, nil
+}
@@ -0,0 +1,648 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ resource.Resource                = &s3ObjectResource{}
+	_ resource.ResourceWithConfigure   = &s3ObjectResource{}
+	_ resource.ResourceWithModifyPlan  = &s3ObjectResource{}
+	_ resource.ResourceWithImportState = &s3ObjectResource{}
+)
+
+type s3ObjectResourceModel struct {
+	ID                   types.String `tfsdk:"id"`
+	Last_Updated         types.String `tfsdk:"last_updated"`
+	Bucket               types.String `tfsdk:"bucket"`
+	Key                  types.String `tfsdk:"key"`
+	Source               types.String `tfsdk:"source"`
+	Content              types.String `tfsdk:"content"`
+	ContentBase64        types.String `tfsdk:"content_base64"`
+	ContentType          types.String `tfsdk:"content_type"`
+	CacheControl         types.String `tfsdk:"cache_control"`
+	ContentDisposition   types.String `tfsdk:"content_disposition"`
+	Metadata             types.Map    `tfsdk:"metadata"`
+	StorageClass         types.String `tfsdk:"storage_class"`
+	ServerSideEncryption types.String `tfsdk:"server_side_encryption"`
+	KMSKeyID             types.String `tfsdk:"kms_key_id"`
+	Tags                 types.Map    `tfsdk:"tags"`
+	PartSizeMB           types.Int64  `tfsdk:"part_size_mb"`
+	UploadConcurrency    types.Int64  `tfsdk:"upload_concurrency"`
+	UseAccelerate        types.Bool   `tfsdk:"use_accelerate_endpoint"`
+	ContentSHA256        types.String `tfsdk:"content_sha256"`
+	ChecksumSHA256       types.String `tfsdk:"checksum_sha256"`
+	ETag                 types.String `tfsdk:"etag"`
+	VersionID            types.String `tfsdk:"version_id"`
+}
+
+// NewS3ObjectResource is a helper function to simplify the provider implementation.
+func NewS3ObjectResource() resource.Resource {
+	return &s3ObjectResource{}
+}
+
+// s3ObjectResource uploads a single object from a local file or inline content.
+type s3ObjectResource struct {
+	client *ClientS3
+}
+
+func (r *s3ObjectResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the developer", req.ProviderData),
+		)
+		return
+	}
+	r.client = client
+}
+
+// Metadata returns the resource type name.
+func (r *s3ObjectResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_object"
+}
+
+var s3MetadataKeyPattern = regexp.MustCompile(`^[a-z0-9!#$%&'*+.^_|~-]+$`)
+
+// Schema defines the schema for the resource.
+func (r *s3ObjectResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	storageClasses := make([]string, 0)
+	for _, class := range awstypes.StorageClass("").Values() {
+		storageClasses = append(storageClasses, string(class))
+	}
+	encryptions := make([]string, 0)
+	for _, encryption := range awstypes.ServerSideEncryption("").Values() {
+		encryptions = append(encryptions, string(encryption))
+	}
+	contentSources := path.Expressions{
+		path.MatchRoot("source"),
+		path.MatchRoot("content"),
+		path.MatchRoot("content_base64"),
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Uploads an object to S3 from a local file or inline content. " +
+			"The object is only uploaded again when its content or headers change.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"last_updated": schema.StringAttribute{
+				Computed: true,
+			},
+			"bucket": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"key": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"source": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to a local file to upload.",
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(contentSources...),
+				},
+			},
+			"content": schema.StringAttribute{
+				Optional:    true,
+				Description: "Literal UTF-8 content to upload.",
+			},
+			"content_base64": schema.StringAttribute{
+				Optional:    true,
+				Description: "Base64-encoded binary content to upload.",
+			},
+			"content_type": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "MIME type of the object. Detected from the key or source extension, then the content, when not set.",
+			},
+			"cache_control": schema.StringAttribute{
+				Optional: true,
+			},
+			"content_disposition": schema.StringAttribute{
+				Optional: true,
+			},
+			"metadata": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "User metadata stored as x-amz-meta-* headers. Keys must be lowercase.",
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.RegexMatches(s3MetadataKeyPattern, "must be lowercase; S3 lowercases metadata keys")),
+				},
+			},
+			"storage_class": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Storage class of the object. Defaults to STANDARD.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(storageClasses...),
+				},
+			},
+			"server_side_encryption": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Server-side encryption algorithm. Defaults to the bucket's default encryption.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(encryptions...),
+				},
+			},
+			"kms_key_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "KMS key used when server_side_encryption is aws:kms or aws:kms:dsse.",
+			},
+			"tags": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"part_size_mb": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(16),
+				Description: "Part size in MiB for multipart uploads. Source files larger than one part are uploaded in parts.",
+				Validators: []validator.Int64{
+					int64validator.Between(5, 5120),
+				},
+			},
+			"upload_concurrency": schema.Int64Attribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(4),
+				Description: "Number of parts uploaded in parallel.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 64),
+				},
+			},
+			"use_accelerate_endpoint": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Upload through the Transfer Acceleration endpoint. The bucket must have transfer_acceleration enabled.",
+			},
+			"content_sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "Hex SHA-256 of the content. A change triggers a new upload.",
+			},
+			"checksum_sha256": schema.StringAttribute{
+				Computed:    true,
+				Description: "SHA-256 checksum S3 stores for the object. For multipart uploads this is the composite checksum of the parts.",
+			},
+			"etag": schema.StringAttribute{
+				Computed: true,
+			},
+			"version_id": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+// ModifyPlan hashes the local content so that only real content changes plan an upload, and
+// fills in the detected content type.
+func (r *s3ObjectResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to compute on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan s3ObjectResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var configuredType types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("content_type"), &configuredType)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var sum, checksum, detectedType string
+	switch {
+	case !plan.Source.IsNull():
+		if plan.Source.IsUnknown() || plan.PartSizeMB.IsUnknown() {
+			return
+		}
+		digest, err := digestS3Source(plan.Source.ValueString(), plan.multipartOptions())
+		if err == nil && configuredType.IsNull() {
+			detectedType, err = detectFileContentType(plan.Source.ValueString())
+		}
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("source"), "Unable to Read Source File", err.Error())
+			return
+		}
+		sum, checksum = digest.SHA256, digest.Checksum
+	case !plan.Content.IsNull():
+		if plan.Content.IsUnknown() {
+			return
+		}
+		sum = sha256Bytes([]byte(plan.Content.ValueString()))
+		detectedType = detectContentType(plan.Key.ValueString(), []byte(plan.Content.ValueString()))
+	case !plan.ContentBase64.IsNull():
+		if plan.ContentBase64.IsUnknown() {
+			return
+		}
+		data, err := base64.StdEncoding.DecodeString(plan.ContentBase64.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("content_base64"), "Invalid Base64 Content", err.Error())
+			return
+		}
+		sum = sha256Bytes(data)
+		detectedType = detectContentType(plan.Key.ValueString(), data)
+	default:
+		return
+	}
+
+	if checksum == "" {
+		raw, _ := hex.DecodeString(sum)
+		checksum = base64.StdEncoding.EncodeToString(raw)
+	}
+
+	plan.ContentSHA256 = types.StringValue(sum)
+	plan.ChecksumSHA256 = types.StringValue(checksum)
+	if configuredType.IsNull() && !plan.Key.IsUnknown() {
+		plan.ContentType = types.StringValue(detectedType)
+	}
+
+	var priorSum, priorChecksum types.String
+	if !req.State.Raw.IsNull() {
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("content_sha256"), &priorSum)...)
+		resp.Diagnostics.Append(req.State.GetAttribute(ctx, path.Root("checksum_sha256"), &priorChecksum)...)
+	}
+	// Keep the checksum exactly as S3 reported it when it describes the same content.
+	if sameS3Checksum(priorChecksum.ValueString(), checksum) {
+		plan.ChecksumSHA256 = priorChecksum
+	}
+
+	// Terraform sees no configuration change when only the file on disk (or the object in
+	// S3) changed, so the values an upload replaces have to be marked unknown here.
+	if !priorSum.Equal(plan.ContentSHA256) || !priorChecksum.Equal(plan.ChecksumSHA256) {
+		plan.ETag = types.StringUnknown()
+		plan.VersionID = types.StringUnknown()
+		plan.Last_Updated = types.StringUnknown()
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
+// Create creates the resource and sets the initial Terraform state.
+func (r *s3ObjectResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan s3ObjectResourceModel
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.upload(ctx, &plan, resp.Diagnostics.AddError)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (r *s3ObjectResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state s3ObjectResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	svc := r.client.S3Client
+	bucket, key := state.Bucket.ValueString(), state.Key.ValueString()
+
+	head, err := svc.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket:       aws.String(bucket),
+		Key:          aws.String(key),
+		ChecksumMode: awstypes.ChecksumModeEnabled,
+	})
+	if err != nil {
+		if isS3ErrorCode(err, "NotFound", "NoSuchKey", "NoSuchBucket") {
+			resp.State.RemoveResource(ctx)
+			return
+		}
+		resp.Diagnostics.AddError(
+			"Error reading object",
+			"Could not read s3://"+bucket+"/"+key+": "+err.Error(),
+		)
+		return
+	}
+
+	tagging, err := svc.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"Error reading object tags",
+			"Could not read tags of s3://"+bucket+"/"+key+": "+err.Error(),
+		)
+		return
+	}
+
+	state.ID = types.StringValue(bucket + "/" + key)
+	state.ContentType = types.StringPointerValue(head.ContentType)
+	state.CacheControl = types.StringPointerValue(head.CacheControl)
+	state.ContentDisposition = types.StringPointerValue(head.ContentDisposition)
+	state.StorageClass = types.StringValue(string(awstypes.StorageClassStandard))
+	if head.StorageClass != "" {
+		state.StorageClass = types.StringValue(string(head.StorageClass))
+	}
+	state.ServerSideEncryption = types.StringValue(string(head.ServerSideEncryption))
+	state.ETag = types.StringPointerValue(head.ETag)
+	state.VersionID = types.StringValue(aws.ToString(head.VersionId))
+	if head.ChecksumSHA256 != nil {
+		state.ChecksumSHA256 = types.StringPointerValue(head.ChecksumSHA256)
+		if sum := s3ChecksumToHex(*head.ChecksumSHA256); sum != "" {
+			state.ContentSHA256 = types.StringValue(sum)
+		}
+	}
+
+	state.Metadata, diags = stringMapValue(ctx, head.Metadata, state.Metadata)
+	resp.Diagnostics.Append(diags...)
+	state.Tags, diags = stringMapValue(ctx, s3TagsToMap(tagging.TagSet), state.Tags)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update updates the resource and sets the updated Terraform state on success.
+func (r *s3ObjectResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state s3ObjectResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if s3ObjectNeedsUpload(plan, state) {
+		r.upload(ctx, &plan, resp.Diagnostics.AddError)
+	} else {
+		r.retag(ctx, &plan, state, resp.Diagnostics.AddError)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags := resp.State.Set(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete deletes the resource and removes the Terraform state on success.
+func (r *s3ObjectResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state s3ObjectResourceModel
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, err := r.client.S3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(state.Bucket.ValueString()),
+		Key:    aws.String(state.Key.ValueString()),
+	})
+	if err != nil && !isS3ErrorCode(err, "NoSuchBucket") {
+		resp.Diagnostics.AddError(
+			"Error deleting object",
+			"Could not delete s3://"+state.Bucket.ValueString()+"/"+state.Key.ValueString()+": "+err.Error(),
+		)
+	}
+}
+
+// ImportState imports an existing object from an ID of the form bucket/key.
+func (r *s3ObjectResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	bucket, key, found := strings.Cut(req.ID, "/")
+	if !found || bucket == "" || key == "" {
+		resp.Diagnostics.AddError(
+			"Unexpected Import Identifier",
+			fmt.Sprintf("Expected an import identifier of the form bucket/key, got: %q", req.ID),
+		)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), req.ID)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("bucket"), bucket)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key"), key)...)
+}
+
+// upload sends the planned content and headers to S3 and records what S3 stored.
+func (r *s3ObjectResource) upload(ctx context.Context, plan *s3ObjectResourceModel, addError func(string, string)) {
+	upload := s3ObjectUpload{
+		Bucket:               plan.Bucket.ValueString(),
+		Key:                  plan.Key.ValueString(),
+		ContentType:          plan.ContentType.ValueString(),
+		CacheControl:         plan.CacheControl.ValueString(),
+		ContentDisposition:   plan.ContentDisposition.ValueString(),
+		StorageClass:         plan.StorageClass.ValueString(),
+		ServerSideEncryption: plan.ServerSideEncryption.ValueString(),
+		KMSKeyID:             plan.KMSKeyID.ValueString(),
+	}
+	if diags := plan.Metadata.ElementsAs(ctx, &upload.Metadata, false); diags.HasError() {
+		addError("Invalid Metadata", "Object metadata must be a map of strings.")
+		return
+	}
+	if diags := plan.Tags.ElementsAs(ctx, &upload.Tags, false); diags.HasError() {
+		addError("Invalid Tags", "Object tags must be a map of strings.")
+		return
+	}
+
+	result, err := r.send(ctx, *plan, upload)
+	if err != nil {
+		addError(
+			"Error uploading object",
+			"Could not upload s3://"+upload.Bucket+"/"+upload.Key+": "+err.Error(),
+		)
+		return
+	}
+
+	// Read back the values S3 fills in when they were left unset.
+	headInput := &s3.HeadObjectInput{
+		Bucket: aws.String(upload.Bucket),
+		Key:    aws.String(upload.Key),
+	}
+	if result.VersionID != "" {
+		headInput.VersionId = aws.String(result.VersionID)
+	}
+	head, err := r.client.S3Client.HeadObject(ctx, headInput)
+	if err != nil {
+		addError(
+			"Error reading object",
+			"Could not read back s3://"+upload.Bucket+"/"+upload.Key+": "+err.Error(),
+		)
+		return
+	}
+
+	plan.ID = types.StringValue(upload.Bucket + "/" + upload.Key)
+	if plan.ChecksumSHA256.IsUnknown() || !sameS3Checksum(plan.ChecksumSHA256.ValueString(), result.ChecksumSHA256) {
+		plan.ChecksumSHA256 = types.StringValue(result.ChecksumSHA256)
+	}
+	plan.ETag = types.StringValue(result.ETag)
+	plan.VersionID = types.StringValue(result.VersionID)
+	plan.ContentType = types.StringPointerValue(head.ContentType)
+	plan.StorageClass = types.StringValue(string(awstypes.StorageClassStandard))
+	if head.StorageClass != "" {
+		plan.StorageClass = types.StringValue(string(head.StorageClass))
+	}
+	plan.ServerSideEncryption = types.StringValue(string(head.ServerSideEncryption))
+	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
+}
+
+// send uploads the content in one request, or in parallel parts when the source file is
+// larger than a single part.
+func (r *s3ObjectResource) send(ctx context.Context, plan s3ObjectResourceModel, upload s3ObjectUpload) (s3UploadResult, error) {
+	if plan.Source.IsNull() {
+		body, err := plan.body()
+		if err != nil {
+			return s3UploadResult{}, err
+		}
+		defer body.Close()
+		return putS3Object(ctx, r.client.uploadClient(plan.UseAccelerate.ValueBool()), upload, body)
+	}
+
+	return uploadS3File(ctx, r.client.uploadClient(plan.UseAccelerate.ValueBool()), upload, plan.Source.ValueString(), plan.multipartOptions())
+}
+
+// retag replaces only the object's tags, for updates that leave content and headers alone.
+func (r *s3ObjectResource) retag(ctx context.Context, plan *s3ObjectResourceModel, state s3ObjectResourceModel, addError func(string, string)) {
+	bucket, key := plan.Bucket.ValueString(), plan.Key.ValueString()
+
+	var tags map[string]string
+	if diags := plan.Tags.ElementsAs(ctx, &tags, false); diags.HasError() {
+		addError("Invalid Tags", "Object tags must be a map of strings.")
+		return
+	}
+
+	var err error
+	if len(tags) == 0 {
+		_, err = r.client.S3Client.DeleteObjectTagging(ctx, &s3.DeleteObjectTaggingInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(key),
+		})
+	} else {
+		_, err = r.client.S3Client.PutObjectTagging(ctx, &s3.PutObjectTaggingInput{
+			Bucket:  aws.String(bucket),
+			Key:     aws.String(key),
+			Tagging: &awstypes.Tagging{TagSet: s3TagsFromMap(tags)},
+		})
+	}
+	if err != nil {
+		addError(
+			"Error tagging object",
+			"Could not update tags of s3://"+bucket+"/"+key+": "+err.Error(),
+		)
+		return
+	}
+
+	plan.ID = state.ID
+	plan.ETag = state.ETag
+	plan.VersionID = state.VersionID
+	if plan.StorageClass.IsUnknown() {
+		plan.StorageClass = state.StorageClass
+	}
+	if plan.ServerSideEncryption.IsUnknown() {
+		plan.ServerSideEncryption = state.ServerSideEncryption
+	}
+	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
+}
+
+// s3ObjectNeedsUpload reports whether an update changes anything besides the tags, which
+// are the only object setting S3 can change in place.
+func s3ObjectNeedsUpload(plan, state s3ObjectResourceModel) bool {
+	return !plan.ContentSHA256.Equal(state.ContentSHA256) ||
+		!plan.ChecksumSHA256.Equal(state.ChecksumSHA256) ||
+		!plan.ContentType.Equal(state.ContentType) ||
+		!plan.CacheControl.Equal(state.CacheControl) ||
+		!plan.ContentDisposition.Equal(state.ContentDisposition) ||
+		!plan.Metadata.Equal(state.Metadata) ||
+		(!plan.StorageClass.IsUnknown() && !plan.StorageClass.Equal(state.StorageClass)) ||
+		(!plan.ServerSideEncryption.IsUnknown() && !plan.ServerSideEncryption.Equal(state.ServerSideEncryption)) ||
+		!plan.KMSKeyID.Equal(state.KMSKeyID)
+}
+
+// multipartOptions returns the configured part size and concurrency.
+func (m s3ObjectResourceModel) multipartOptions() s3MultipartOptions {
+	return s3MultipartOptions{
+		PartSize:    m.PartSizeMB.ValueInt64() * 1024 * 1024,
+		Concurrency: int(m.UploadConcurrency.ValueInt64()),
+	}
+}
+
+// body opens the configured content source for upload.
+func (m s3ObjectResourceModel) body() (io.ReadSeekCloser, error) {
+	switch {
+	case !m.Source.IsNull():
+		return os.Open(m.Source.ValueString())
+	case !m.ContentBase64.IsNull():
+		data, err := base64.StdEncoding.DecodeString(m.ContentBase64.ValueString())
+		if err != nil {
+			return nil, err
+		}
+		return nopSeekCloser{bytes.NewReader(data)}, nil
+	default:
+		return nopSeekCloser{strings.NewReader(m.Content.ValueString())}, nil
+	}
+}
+
+type nopSeekCloser struct {
+	io.ReadSeeker
+}
+
+func (nopSeekCloser) Close() error { return nil }
+
+// stringMapValue converts a Go string map into a Terraform map. An empty map stays
+// empty when prior was an empty map, so "tags = {}" does not diff on every plan,
+// and is null otherwise.
+func stringMapValue(ctx context.Context, values map[string]string, prior types.Map) (types.Map, diag.Diagnostics) {
+	if len(values) == 0 && (prior.IsNull() || prior.IsUnknown()) {
+		return types.MapNull(types.StringType), nil
+	}
+	if values == nil {
+		values = map[string]string{}
+	}
+	return types.MapValueFrom(ctx, types.StringType, values)
+}
@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestStringMapValue(t *testing.T) {
+	ctx := context.Background()
+	empty := types.MapValueMust(types.StringType, map[string]attr.Value{})
+	cases := []struct {
+		name   string
+		values map[string]string
+		prior  types.Map
+		want   types.Map
+	}{
+		{"empty after null", nil, types.MapNull(types.StringType), types.MapNull(types.StringType)},
+		{"empty after unknown", map[string]string{}, types.MapUnknown(types.StringType), types.MapNull(types.StringType)},
+		{"empty after empty", nil, empty, empty},
+		{"empty after values", nil, types.MapValueMust(types.StringType, map[string]attr.Value{"a": types.StringValue("b")}), empty},
+		{"values after null", map[string]string{"a": "b"}, types.MapNull(types.StringType), types.MapValueMust(types.StringType, map[string]attr.Value{"a": types.StringValue("b")})},
+	}
+	for _, c := range cases {
+		got, diags := stringMapValue(ctx, c.values, c.prior)
+		if diags.HasError() {
+			t.Fatalf("%s: %v", c.name, diags)
+		}
+		if !got.Equal(c.want) {
+			t.Errorf("%s: got %s, want %s", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &s3ObjectsDataSource{}
+	_ datasource.DataSourceWithConfigure = &s3ObjectsDataSource{}
+)
+
+// NewS3ObjectsDataSource is a helper function to simplify the provider implementation.
+func NewS3ObjectsDataSource() datasource.DataSource {
+	return &s3ObjectsDataSource{}
+}
+
+// s3ObjectsDataSource lists the objects under a prefix of a bucket.
+type s3ObjectsDataSource struct {
+	client *ClientS3
+}
+
+type s3ObjectsDataSourceModel struct {
+	Bucket         types.String         `tfsdk:"bucket"`
+	Prefix         types.String         `tfsdk:"prefix"`
+	Delimiter      types.String         `tfsdk:"delimiter"`
+	StartAfter     types.String         `tfsdk:"start_after"`
+	MaxKeys        types.Int64          `tfsdk:"max_keys"`
+	FetchTags      types.Bool           `tfsdk:"fetch_tags"`
+	Objects        []s3ObjectEntryModel `tfsdk:"objects"`
+	CommonPrefixes []string             `tfsdk:"common_prefixes"`
+}
+
+type s3ObjectEntryModel struct {
+	Key          types.String      `tfsdk:"key"`
+	Size         types.Int64       `tfsdk:"size"`
+	ETag         types.String      `tfsdk:"etag"`
+	LastModified types.String      `tfsdk:"last_modified"`
+	StorageClass types.String      `tfsdk:"storage_class"`
+	Tags         map[string]string `tfsdk:"tags"`
+}
+
+// Metadata returns the data source type name.
+func (d *s3ObjectsDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_objects"
+}
+
+// Schema defines the schema for the data source.
+func (d *s3ObjectsDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Lists the objects in an S3 bucket, optionally under a prefix. Directory buckets only support \"/\" as delimiter " +
+			"and prefixes ending in it.",
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required: true,
+			},
+			"prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list keys starting with this prefix.",
+			},
+			"delimiter": schema.StringAttribute{
+				Optional:    true,
+				Description: "Group keys sharing the part up to the next delimiter into common_prefixes, for example \"/\".",
+			},
+			"start_after": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only list keys that sort after this key. Not supported for directory buckets.",
+			},
+			"max_keys": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of keys and common prefixes to return. All are returned when unset.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"fetch_tags": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Also read the tags of every listed object. This costs one request per object.",
+			},
+			"objects": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							Computed: true,
+						},
+						"size": schema.Int64Attribute{
+							Computed: true,
+						},
+						"etag": schema.StringAttribute{
+							Computed: true,
+						},
+						"last_modified": schema.StringAttribute{
+							Computed:    true,
+							Description: "RFC 3339 timestamp of the last modification.",
+						},
+						"storage_class": schema.StringAttribute{
+							Computed: true,
+						},
+						"tags": schema.MapAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+							Description: "Object tags, only read when fetch_tags is true.",
+						},
+					},
+				},
+			},
+			"common_prefixes": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *s3ObjectsDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state s3ObjectsDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Directory buckets list keys in no particular order, so the whole listing is read and
+	// sorted before max_keys applies.
+	directory := isS3DirectoryBucket(state.Bucket.ValueString())
+	if directory {
+		if err := validateS3DirectoryBucketListing(state.Prefix.ValueString(), state.Delimiter.ValueString()); err != nil {
+			resp.Diagnostics.AddError("Unsupported Directory Bucket Listing", err.Error())
+		}
+		if !state.StartAfter.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("start_after"), "Unsupported Directory Bucket Listing", "Directory buckets do not support start_after.")
+		}
+		if state.FetchTags.ValueBool() {
+			resp.Diagnostics.AddAttributeError(path.Root("fetch_tags"), "Unsupported Directory Bucket Listing", "Directory buckets do not support object tags.")
+		}
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	input := &s3.ListObjectsV2Input{
+		Bucket:     aws.String(state.Bucket.ValueString()),
+		Prefix:     state.Prefix.ValueStringPointer(),
+		Delimiter:  state.Delimiter.ValueStringPointer(),
+		StartAfter: state.StartAfter.ValueStringPointer(),
+	}
+	limit := int(state.MaxKeys.ValueInt64())
+	if limit > 0 && limit < 1000 && !directory {
+		input.MaxKeys = aws.Int32(int32(limit))
+	}
+
+	state.Objects = []s3ObjectEntryModel{}
+	state.CommonPrefixes = []string{}
+	paginator := s3.NewListObjectsV2Paginator(d.client.S3Client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"unable to list objects",
+				"Could not list objects in bucket "+state.Bucket.ValueString()+": "+err.Error(),
+			)
+			return
+		}
+		for _, object := range page.Contents {
+			state.Objects = append(state.Objects, s3ObjectEntryModel{
+				Key:          types.StringPointerValue(object.Key),
+				Size:         types.Int64Value(aws.ToInt64(object.Size)),
+				ETag:         types.StringPointerValue(object.ETag),
+				LastModified: types.StringValue(aws.ToTime(object.LastModified).Format(time.RFC3339)),
+				StorageClass: types.StringValue(string(object.StorageClass)),
+			})
+		}
+		for _, prefix := range page.CommonPrefixes {
+			state.CommonPrefixes = append(state.CommonPrefixes, aws.ToString(prefix.Prefix))
+		}
+		if limit > 0 && !directory && len(state.Objects)+len(state.CommonPrefixes) >= limit {
+			break
+		}
+	}
+	if directory {
+		sort.Slice(state.Objects, func(i, j int) bool {
+			return state.Objects[i].Key.ValueString() < state.Objects[j].Key.ValueString()
+		})
+		sort.Strings(state.CommonPrefixes)
+	}
+	state.Objects, state.CommonPrefixes = capS3Listing(state.Objects, state.CommonPrefixes, limit)
+
+	if state.FetchTags.ValueBool() {
+		indexes := make([]int, len(state.Objects))
+		for index := range indexes {
+			indexes[index] = index
+		}
+		err := forEachConcurrently(ctx, 10, indexes, func(ctx context.Context, index int) error {
+			out, err := d.client.S3Client.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+				Bucket: aws.String(state.Bucket.ValueString()),
+				Key:    aws.String(state.Objects[index].Key.ValueString()),
+			})
+			if err != nil {
+				return fmt.Errorf("%s: %w", state.Objects[index].Key.ValueString(), err)
+			}
+			state.Objects[index].Tags = s3TagsToMap(out.TagSet)
+			return nil
+		})
+		if err != nil {
+			resp.Diagnostics.AddError("unable to read object tags", err.Error())
+			return
+		}
+	}
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// capS3Listing trims a listing to limit entries in key order, counting objects and common
+// prefixes together the way ListObjectsV2 does. A limit of zero means no cap.
+func capS3Listing(objects []s3ObjectEntryModel, prefixes []string, limit int) ([]s3ObjectEntryModel, []string) {
+	if limit <= 0 || len(objects)+len(prefixes) <= limit {
+		return objects, prefixes
+	}
+	keptObjects, keptPrefixes := 0, 0
+	for keptObjects+keptPrefixes < limit {
+		switch {
+		case keptObjects == len(objects):
+			keptPrefixes++
+		case keptPrefixes == len(prefixes):
+			keptObjects++
+		case objects[keptObjects].Key.ValueString() < prefixes[keptPrefixes]:
+			keptObjects++
+		default:
+			keptPrefixes++
+		}
+	}
+	return objects[:keptObjects], prefixes[:keptPrefixes]
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *s3ObjectsDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
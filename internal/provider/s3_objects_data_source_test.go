@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCapS3Listing(t *testing.T) {
+	objects := []s3ObjectEntryModel{
+		{Key: types.StringValue("a.txt")},
+		{Key: types.StringValue("c.txt")},
+		{Key: types.StringValue("e.txt")},
+	}
+	prefixes := []string{"b/", "d/"}
+
+	gotObjects, gotPrefixes := capS3Listing(objects, prefixes, 3)
+	if len(gotObjects) != 2 || len(gotPrefixes) != 1 {
+		t.Fatalf("got %d objects and %d prefixes, want 2 and 1", len(gotObjects), len(gotPrefixes))
+	}
+	if gotObjects[1].Key.ValueString() != "c.txt" || gotPrefixes[0] != "b/" {
+		t.Errorf("kept the wrong entries: %v %v", gotObjects, gotPrefixes)
+	}
+
+	gotObjects, gotPrefixes = capS3Listing(objects, prefixes, 0)
+	if len(gotObjects) != 3 || len(gotPrefixes) != 2 {
+		t.Errorf("expected no cap for a zero limit")
+	}
+}
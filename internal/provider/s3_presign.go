@@ -0,0 +1,176 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+const (
+	s3PresignDefaultExpiry = 15 * time.Minute
+	// s3PresignMaxExpiry is the longest validity SigV4 query signing allows.
+	s3PresignMaxExpiry = 7 * 24 * time.Hour
+)
+
+// s3PresignMethods are the HTTP methods a presigned URL can be generated for.
+var s3PresignMethods = []string{http.MethodGet, http.MethodPut, http.MethodDelete}
+
+// s3PresignRequest describes a presigned object URL. ContentType and ChecksumSHA256 only
+// apply to PUT and become part of the signature, so an upload with a different value is
+// rejected. A zero SignedAt signs with the current time.
+type s3PresignRequest struct {
+	Bucket         string
+	Key            string
+	Method         string
+	Expires        time.Duration
+	VersionID      string
+	ContentType    string
+	ChecksumSHA256 string
+	SignedAt       time.Time
+}
+
+// s3PresignResult is a presigned URL together with the headers the caller has to send.
+type s3PresignResult struct {
+	URL       string
+	Method    string
+	Headers   map[string]string
+	ExpiresAt time.Time
+}
+
+func (r s3PresignRequest) validate() error {
+	if r.Bucket == "" || r.Key == "" {
+		return fmt.Errorf("bucket and key must not be empty")
+	}
+	method := strings.ToUpper(r.Method)
+	valid := false
+	for _, allowed := range s3PresignMethods {
+		valid = valid || method == allowed
+	}
+	if !valid {
+		return fmt.Errorf("method must be one of %s, got %q", strings.Join(s3PresignMethods, ", "), r.Method)
+	}
+	if r.Expires < time.Second || r.Expires > s3PresignMaxExpiry {
+		return fmt.Errorf("expiry must be between 1 second and %s, got %s", s3PresignMaxExpiry, r.Expires)
+	}
+	if method != http.MethodPut && (r.ContentType != "" || r.ChecksumSHA256 != "") {
+		return fmt.Errorf("content type and checksum constraints only apply to PUT")
+	}
+	if method == http.MethodPut && r.VersionID != "" {
+		return fmt.Errorf("version ID does not apply to PUT")
+	}
+	return nil
+}
+
+// presignS3Object signs an object request with the credentials of svc without sending it.
+func presignS3Object(ctx context.Context, svc *s3.Client, r s3PresignRequest) (*s3PresignResult, error) {
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	signedAt := r.SignedAt
+	if signedAt.IsZero() {
+		signedAt = time.Now()
+	}
+	signedAt = signedAt.UTC().Truncate(time.Second)
+
+	presigner := s3.NewPresignClient(svc, func(o *s3.PresignOptions) {
+		o.Expires = r.Expires
+		o.Presigner = fixedTimePresigner{
+			// S3 signs the key as sent rather than escaping the path a second time.
+			presigner: v4.NewSigner(func(so *v4.SignerOptions) { so.DisableURIPathEscaping = true }),
+			signedAt:  signedAt,
+		}
+	})
+
+	var (
+		signed *v4.PresignedHTTPRequest
+		err    error
+	)
+	switch strings.ToUpper(r.Method) {
+	case http.MethodGet:
+		signed, err = presigner.PresignGetObject(ctx, &s3.GetObjectInput{
+			Bucket:    aws.String(r.Bucket),
+			Key:       aws.String(r.Key),
+			VersionId: optionalString(r.VersionID),
+		})
+	case http.MethodPut:
+		var withContentType []func(*s3.PresignOptions)
+		if r.ContentType != "" {
+			withContentType = append(withContentType, s3.WithPresignClientFromClientOptions(func(o *s3.Options) {
+				o.APIOptions = append(o.APIOptions, signContentTypeHeader(r.ContentType))
+			}))
+		}
+		signed, err = presigner.PresignPutObject(ctx, &s3.PutObjectInput{
+			Bucket:         aws.String(r.Bucket),
+			Key:            aws.String(r.Key),
+			ChecksumSHA256: optionalString(r.ChecksumSHA256),
+		}, withContentType...)
+	case http.MethodDelete:
+		signed, err = presigner.PresignDeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket:    aws.String(r.Bucket),
+			Key:       aws.String(r.Key),
+			VersionId: optionalString(r.VersionID),
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(map[string]string)
+	for name, values := range signed.SignedHeader {
+		// Host is derived from the URL by every HTTP client.
+		if strings.EqualFold(name, "Host") {
+			continue
+		}
+		headers[name] = strings.Join(values, ",")
+	}
+	return &s3PresignResult{
+		URL:       signed.URL,
+		Method:    signed.Method,
+		Headers:   headers,
+		ExpiresAt: signedAt.Add(r.Expires),
+	}, nil
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return aws.String(value)
+}
+
+// fixedTimePresigner signs with a caller-chosen time instead of the clock, so the same
+// inputs produce the same URL.
+type fixedTimePresigner struct {
+	presigner *v4.Signer
+	signedAt  time.Time
+}
+
+func (p fixedTimePresigner) PresignHTTP(
+	ctx context.Context, credentials aws.Credentials, r *http.Request,
+	payloadHash string, service string, region string, _ time.Time,
+	optFns ...func(*v4.SignerOptions),
+) (string, http.Header, error) {
+	return p.presigner.PresignHTTP(ctx, credentials, r, payloadHash, service, region, p.signedAt, optFns...)
+}
+
+// signContentTypeHeader puts Content-Type back on a presigned PUT. The SDK strips it
+// before signing, which would let the URL be used with any content type.
+func signContentTypeHeader(contentType string) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Build.Add(middleware.BuildMiddlewareFunc("SignContentType",
+			func(ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler) (middleware.BuildOutput, middleware.Metadata, error) {
+				if req, ok := in.Request.(*smithyhttp.Request); ok {
+					req.Header.Set("Content-Type", contentType)
+				}
+				return next.HandleBuild(ctx, in)
+			}), middleware.After)
+	}
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestPresignS3Object(t *testing.T) {
+	svc := s3.New(s3.Options{
+		Region:      "us-east-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+	})
+	request := s3PresignRequest{
+		Bucket:      "bucket",
+		Key:         "uploads/vm 1.tar",
+		Method:      "PUT",
+		Expires:     time.Hour,
+		ContentType: "application/x-tar",
+		SignedAt:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+
+	first, err := presignS3Object(context.Background(), svc, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := presignS3Object(context.Background(), svc, request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.URL != second.URL {
+		t.Errorf("signing twice with a fixed time produced different URLs:\n%s\n%s", first.URL, second.URL)
+	}
+	if !strings.Contains(first.URL, "X-Amz-Date=20240501T120000Z") || !strings.Contains(first.URL, "X-Amz-Expires=3600") {
+		t.Errorf("URL does not carry the signing time and expiry: %s", first.URL)
+	}
+	if !strings.Contains(first.URL, "content-type") {
+		t.Errorf("content type is not part of the signature: %s", first.URL)
+	}
+	if got := first.Headers["Content-Type"]; got != "application/x-tar" {
+		t.Errorf("Content-Type header = %q", got)
+	}
+	if want := request.SignedAt.Add(time.Hour); !first.ExpiresAt.Equal(want) {
+		t.Errorf("ExpiresAt = %s, want %s", first.ExpiresAt, want)
+	}
+}
+
+func TestS3PresignRequestValidate(t *testing.T) {
+	valid := s3PresignRequest{Bucket: "b", Key: "k", Method: "GET", Expires: time.Minute}
+	cases := map[string]func(r *s3PresignRequest){
+		"unsupported method":  func(r *s3PresignRequest) { r.Method = "POST" },
+		"expiry too long":     func(r *s3PresignRequest) { r.Expires = 8 * 24 * time.Hour },
+		"content type on GET": func(r *s3PresignRequest) { r.ContentType = "text/plain" },
+		"version on PUT":      func(r *s3PresignRequest) { r.Method, r.VersionID = "PUT", "v1" },
+		"missing key":         func(r *s3PresignRequest) { r.Key = "" },
+	}
+	if err := valid.validate(); err != nil {
+		t.Fatalf("valid request rejected: %v", err)
+	}
+	for name, mutate := range cases {
+		request := valid
+		mutate(&request)
+		if err := request.validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
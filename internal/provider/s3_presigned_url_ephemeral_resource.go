@@ -0,0 +1,165 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ ephemeral.EphemeralResource              = &s3PresignedURLEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &s3PresignedURLEphemeralResource{}
+)
+
+// NewS3PresignedURLEphemeralResource is a helper function to simplify the provider implementation.
+func NewS3PresignedURLEphemeralResource() ephemeral.EphemeralResource {
+	return &s3PresignedURLEphemeralResource{}
+}
+
+// s3PresignedURLEphemeralResource produces presigned object URLs. Being ephemeral, the
+// URL is never written to plan or state files.
+type s3PresignedURLEphemeralResource struct {
+	client *ClientS3
+}
+
+type s3PresignedURLModel struct {
+	Bucket         types.String `tfsdk:"bucket"`
+	Key            types.String `tfsdk:"key"`
+	Method         types.String `tfsdk:"method"`
+	ExpiresIn      types.Int64  `tfsdk:"expires_in"`
+	VersionID      types.String `tfsdk:"version_id"`
+	ContentType    types.String `tfsdk:"content_type"`
+	ChecksumSHA256 types.String `tfsdk:"checksum_sha256"`
+	URL            types.String `tfsdk:"url"`
+	Headers        types.Map    `tfsdk:"headers"`
+	ExpiresAt      types.String `tfsdk:"expires_at"`
+}
+
+// Metadata returns the ephemeral resource type name.
+func (e *s3PresignedURLEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_presigned_url"
+}
+
+// Schema defines the schema for the ephemeral resource.
+func (e *s3PresignedURLEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Generates a presigned URL that grants temporary access to one S3 object.",
+		Attributes: map[string]schema.Attribute{
+			"bucket": schema.StringAttribute{
+				Required: true,
+			},
+			"key": schema.StringAttribute{
+				Required: true,
+			},
+			"method": schema.StringAttribute{
+				Optional:    true,
+				Description: "HTTP method the URL is valid for: GET, PUT or DELETE. Defaults to GET.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(s3PresignMethods...),
+				},
+			},
+			"expires_in": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Validity of the URL in seconds, at most 604800 (7 days). Defaults to 900.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, int64(s3PresignMaxExpiry/time.Second)),
+				},
+			},
+			"version_id": schema.StringAttribute{
+				Optional:    true,
+				Description: "Object version a GET or DELETE URL is restricted to.",
+			},
+			"content_type": schema.StringAttribute{
+				Optional:    true,
+				Description: "Content type a PUT URL must be used with.",
+			},
+			"checksum_sha256": schema.StringAttribute{
+				Optional:    true,
+				Description: "Base64-encoded SHA-256 the body of a PUT must match.",
+			},
+			"url": schema.StringAttribute{
+				Computed:  true,
+				Sensitive: true,
+			},
+			"headers": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Signed headers that must be sent with the request, such as Content-Type.",
+			},
+			"expires_at": schema.StringAttribute{
+				Computed:    true,
+				Description: "RFC 3339 timestamp after which the URL is rejected.",
+			},
+		},
+	}
+}
+
+// Open generates the presigned URL.
+func (e *s3PresignedURLEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data s3PresignedURLModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	request := s3PresignRequest{
+		Bucket:         data.Bucket.ValueString(),
+		Key:            data.Key.ValueString(),
+		Method:         data.Method.ValueString(),
+		Expires:        time.Duration(data.ExpiresIn.ValueInt64()) * time.Second,
+		VersionID:      data.VersionID.ValueString(),
+		ContentType:    data.ContentType.ValueString(),
+		ChecksumSHA256: data.ChecksumSHA256.ValueString(),
+	}
+	if data.Method.IsNull() {
+		request.Method = "GET"
+	}
+	if data.ExpiresIn.IsNull() {
+		request.Expires = s3PresignDefaultExpiry
+	}
+
+	result, err := presignS3Object(ctx, e.client.S3Client, request)
+	if err != nil {
+		resp.Diagnostics.AddError(
+			"unable to presign object URL",
+			"Could not presign "+request.Method+" s3://"+request.Bucket+"/"+request.Key+": "+err.Error(),
+		)
+		return
+	}
+
+	data.URL = types.StringValue(result.URL)
+	data.ExpiresAt = types.StringValue(result.ExpiresAt.Format(time.RFC3339))
+	headers, diags := types.MapValueFrom(ctx, types.StringType, result.Headers)
+	resp.Diagnostics.Append(diags...)
+	data.Headers = headers
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
+
+// Configure adds the provider configured client to the ephemeral resource.
+func (e *s3PresignedURLEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	e.client = client
+}
@@ -0,0 +1,126 @@
+package provider
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var _ function.Function = &s3PresignedURLFunction{}
+
+// s3PresignedURLOptions are the keys accepted in the options argument.
+var s3PresignedURLOptions = []string{"checksum_sha256", "content_type", "version_id"}
+
+// NewS3PresignedURLFunction is a helper function to simplify the provider implementation.
+func NewS3PresignedURLFunction() function.Function {
+	return &s3PresignedURLFunction{}
+}
+
+// s3PresignedURLFunction is the function counterpart of the xsynchco_s3_presigned_url
+// ephemeral resource, for use in expressions.
+type s3PresignedURLFunction struct{}
+
+// Metadata returns the function name.
+func (f *s3PresignedURLFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "s3_presigned_url"
+}
+
+// Definition defines the parameters and return type of the function.
+func (f *s3PresignedURLFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Generates a presigned URL for an S3 object.",
+		Description: "Signs a GET, PUT or DELETE request for an object with AWS credentials from the environment. " +
+			"The URL grants access to anyone holding it and, like every function result, is written to the plan and " +
+			"the state. Use the xsynchco_s3_presigned_url ephemeral resource to keep it out of both.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name: "bucket",
+			},
+			function.StringParameter{
+				Name: "key",
+			},
+			function.StringParameter{
+				Name:        "method",
+				Description: "GET, PUT or DELETE.",
+			},
+			function.Int64Parameter{
+				Name:        "expires_in",
+				Description: "Validity of the URL in seconds, at most 604800 (7 days).",
+			},
+			function.StringParameter{
+				Name: "signed_at",
+				Description: "RFC 3339 signing time the URL expires relative to, usually plantimestamp(). " +
+					"Terraform requires the same result during plan and apply, so the time is not taken from the clock.",
+			},
+			function.MapParameter{
+				Name:           "options",
+				ElementType:    types.StringType,
+				AllowNullValue: true,
+				Description:    "Optional constraints: version_id (GET/DELETE), and content_type and checksum_sha256 (PUT).",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+// Run generates the presigned URL.
+func (f *s3PresignedURLFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var (
+		bucket, key, method, signedAt string
+		expiresIn                     int64
+		options                       map[string]string
+	)
+	resp.Error = function.ConcatFuncErrors(resp.Error, req.Arguments.Get(ctx, &bucket, &key, &method, &expiresIn, &signedAt, &options))
+	if resp.Error != nil {
+		return
+	}
+
+	request := s3PresignRequest{
+		Bucket:         bucket,
+		Key:            key,
+		Method:         method,
+		Expires:        time.Duration(expiresIn) * time.Second,
+		VersionID:      options["version_id"],
+		ContentType:    options["content_type"],
+		ChecksumSHA256: options["checksum_sha256"],
+	}
+	for name := range options {
+		if !slices.Contains(s3PresignedURLOptions, name) {
+			resp.Error = function.NewArgumentFuncError(5, "Unsupported option "+name+", expected one of "+strings.Join(s3PresignedURLOptions, ", "))
+			return
+		}
+	}
+	parsed, err := time.Parse(time.RFC3339, signedAt)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(4, "signed_at must be an RFC 3339 timestamp: "+err.Error())
+		return
+	}
+	request.SignedAt = parsed
+
+	client, err := functionS3Client()
+	if err != nil {
+		resp.Error = function.NewFuncError("unable to create AWS client: " + err.Error())
+		return
+	}
+	result, err := presignS3Object(ctx, client.S3Client, request)
+	if err != nil {
+		resp.Error = function.NewFuncError("Could not presign " + method + " s3://" + bucket + "/" + key + ": " + err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Error, resp.Result.Set(ctx, result.URL))
+}
+
+// functionS3Client returns the client functions sign with. Terraform may call functions
+// before, or without, configuring the provider, so it is built once from the same
+// environment the aws provider configuration uses rather than shared with the provider.
+var functionS3Client = sync.OnceValues(func() (*ClientS3, error) {
+	return NewClientS3(os.Getenv("S3_REGION"))
+})
@@ -10,16 +10,22 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 // Ensure the implementation satisfies the expected interfaces.
 var (
-	_ resource.Resource              = &s3Resource{}
-	_ resource.ResourceWithConfigure = &s3Resource{}
+	_ resource.Resource               = &s3Resource{}
+	_ resource.ResourceWithConfigure  = &s3Resource{}
+	_ resource.ResourceWithModifyPlan = &s3Resource{}
 )
 
 type s3ResourceModel struct {
@@ -29,9 +35,16 @@ type s3ResourceModel struct {
 }
 
 type buckets struct {
-	Date types.String `tfsdk:"date"`
-	Name types.String `tfsdk:"name"`
-	Tags types.String `tfsdk:"tags"`
+	Date               types.String            `tfsdk:"date"`
+	Name               types.String            `tfsdk:"name"`
+	Tags               types.String            `tfsdk:"tags"`
+	ObjectLockEnabled  types.Bool              `tfsdk:"object_lock_enabled"`
+	ObjectLock         *s3ObjectLockModel      `tfsdk:"object_lock"`
+	Logging            *s3LoggingModel         `tfsdk:"logging"`
+	Acceleration       types.Bool              `tfsdk:"transfer_acceleration"`
+	RequestPayer       types.String            `tfsdk:"request_payer"`
+	AccelerateEndpoint types.String            `tfsdk:"accelerate_endpoint"`
+	Directory          *s3DirectoryBucketModel `tfsdk:"directory_bucket"`
 }
 
 // NewOrderResource is a helper function to simplify the provider implementation.
@@ -88,6 +101,34 @@ func (r *s3Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *r
 						"tags": schema.StringAttribute{
 							Required: true,
 						},
+						"object_lock_enabled": schema.BoolAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(false),
+							Description: "Create the bucket with Object Lock enabled. This can only be set when the bucket is created.",
+						},
+						"object_lock": s3ObjectLockSchema(),
+						"logging":     s3LoggingSchema(),
+						"transfer_acceleration": schema.BoolAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     booldefault.StaticBool(false),
+							Description: "Enable Transfer Acceleration for fast uploads and downloads over long distances.",
+						},
+						"request_payer": schema.StringAttribute{
+							Optional:    true,
+							Computed:    true,
+							Default:     stringdefault.StaticString(string(awstypes.PayerBucketOwner)),
+							Description: "Who pays for requests and data transfer: BucketOwner or Requester.",
+							Validators: []validator.String{
+								stringvalidator.OneOf(string(awstypes.PayerBucketOwner), string(awstypes.PayerRequester)),
+							},
+						},
+						"accelerate_endpoint": schema.StringAttribute{
+							Computed:    true,
+							Description: "Transfer Acceleration host name of the bucket, empty while acceleration is off.",
+						},
+						"directory_bucket": s3DirectoryBucketSchema(),
 					},
 				},
 			},
@@ -95,6 +136,150 @@ func (r *s3Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *r
 	}
 }
 
+// ModifyPlan rejects bucket settings that S3 cannot apply to an existing bucket and fills
+// in the accelerate endpoints.
+func (r *s3Resource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	// Nothing to check on destroy.
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan s3ResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	priorBuckets := make(map[string]buckets)
+	if !req.State.Raw.IsNull() {
+		var state s3ResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		for _, item := range state.Buckets {
+			priorBuckets[item.Name.ValueString()] = item
+		}
+	}
+
+	for index, item := range plan.Buckets {
+		bucketPath := path.Root("buckets").AtListIndex(index)
+
+		if item.ObjectLock != nil && !item.ObjectLockEnabled.IsUnknown() && !item.ObjectLockEnabled.ValueBool() {
+			resp.Diagnostics.AddAttributeError(
+				bucketPath.AtName("object_lock"),
+				"Object Lock Not Enabled",
+				"A default retention rule requires object_lock_enabled = true on the bucket.",
+			)
+		}
+
+		if item.Logging != nil && !item.Name.IsUnknown() && item.Logging.TargetBucket.Equal(item.Name) {
+			resp.Diagnostics.AddAttributeWarning(
+				bucketPath.AtName("logging").AtName("target_bucket"),
+				"Bucket Logs Into Itself",
+				fmt.Sprintf("Bucket %s delivers its access logs to itself. Every log delivery is itself logged, so the log volume keeps growing. Consider a dedicated log bucket.", item.Name.ValueString()),
+			)
+		}
+
+		if item.Acceleration.ValueBool() && strings.Contains(item.Name.ValueString(), ".") {
+			resp.Diagnostics.AddAttributeError(
+				bucketPath.AtName("transfer_acceleration"),
+				"Transfer Acceleration Not Supported",
+				fmt.Sprintf("Bucket %s has a dot in its name, which Transfer Acceleration does not support.", item.Name.ValueString()),
+			)
+		}
+		if !item.Name.IsUnknown() && !item.Acceleration.IsUnknown() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, bucketPath.AtName("accelerate_endpoint"), s3BucketAccelerateEndpoint(item))...)
+		}
+
+		if item.Directory != nil && !item.Name.IsUnknown() {
+			if err := validateS3DirectoryBucket(item.Name.ValueString(), item.Directory); err != nil {
+				resp.Diagnostics.AddAttributeError(bucketPath.AtName("directory_bucket"), "Invalid Directory Bucket", err.Error())
+			}
+			for _, setting := range s3DirectoryBucketUnsupportedSettings(item) {
+				resp.Diagnostics.AddAttributeError(
+					bucketPath.AtName(setting),
+					"Setting Not Supported For Directory Buckets",
+					fmt.Sprintf("Bucket %s is a directory bucket, which does not support %s.", item.Name.ValueString(), setting),
+				)
+			}
+		}
+		if item.Directory == nil && isS3DirectoryBucket(item.Name.ValueString()) {
+			resp.Diagnostics.AddAttributeError(
+				bucketPath.AtName("name"),
+				"Directory Bucket Name",
+				fmt.Sprintf("Names ending in %s are reserved for directory buckets. Add a directory_bucket block to bucket %s or choose another name.", s3DirectoryBucketSuffix, item.Name.ValueString()),
+			)
+		}
+
+		prior, exists := priorBuckets[item.Name.ValueString()]
+		if exists && !item.ObjectLockEnabled.IsUnknown() && !prior.ObjectLockEnabled.Equal(item.ObjectLockEnabled) {
+			resp.Diagnostics.AddAttributeError(
+				bucketPath.AtName("object_lock_enabled"),
+				"Object Lock Cannot Be Changed",
+				fmt.Sprintf("Object Lock can only be set when bucket %s is created, and this resource does not create buckets on update. "+
+					"Keep the current value, or recreate every bucket of this resource with terraform apply -replace, which requires the buckets to be empty.", item.Name.ValueString()),
+			)
+		}
+		if exists && s3DirectoryBucketChanged(prior.Directory, item.Directory) {
+			resp.Diagnostics.AddAttributeError(
+				bucketPath.AtName("directory_bucket"),
+				"Directory Bucket Cannot Be Changed",
+				fmt.Sprintf("The bucket type, location and data redundancy can only be set when bucket %s is created.", item.Name.ValueString()),
+			)
+		}
+	}
+}
+
+// s3DirectoryBucketUnsupportedSettings lists the settings of a directory bucket item that
+// S3 only offers for general purpose buckets.
+func s3DirectoryBucketUnsupportedSettings(item buckets) []string {
+	var settings []string
+	if item.ObjectLockEnabled.ValueBool() {
+		settings = append(settings, "object_lock_enabled")
+	}
+	if item.ObjectLock != nil {
+		settings = append(settings, "object_lock")
+	}
+	if item.Logging != nil {
+		settings = append(settings, "logging")
+	}
+	if item.Acceleration.ValueBool() {
+		settings = append(settings, "transfer_acceleration")
+	}
+	if item.RequestPayer.ValueString() == string(awstypes.PayerRequester) {
+		settings = append(settings, "request_payer")
+	}
+	return settings
+}
+
+// s3DirectoryBucketChanged reports whether a planned directory_bucket differs from the prior
+// one. Unknown planned values are not treated as changes.
+func s3DirectoryBucketChanged(prior, planned *s3DirectoryBucketModel) bool {
+	if prior == nil || planned == nil {
+		return (prior == nil) != (planned == nil)
+	}
+	for _, values := range [][2]types.String{
+		{prior.AvailabilityZoneID, planned.AvailabilityZoneID},
+		{prior.LocationType, planned.LocationType},
+		{prior.DataRedundancy, planned.DataRedundancy},
+	} {
+		if !values[1].IsUnknown() && !values[0].Equal(values[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// s3BucketAccelerateEndpoint is the accelerate_endpoint of a bucket: its Transfer
+// Acceleration host name while acceleration is enabled, otherwise empty.
+func s3BucketAccelerateEndpoint(item buckets) types.String {
+	if !item.Acceleration.ValueBool() {
+		return types.StringValue("")
+	}
+	return types.StringValue(s3AccelerateEndpoint(item.Name.ValueString()))
+}
+
 // Create creates the resource and sets the initial Terraform state.
 func (r *s3Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan s3ResourceModel
@@ -112,12 +297,30 @@ func (r *s3Resource) Create(ctx context.Context, req resource.CreateRequest, res
 
 		awsStringBucket := strings.Replace(item.Name.String(), "\"", "", -1)
 
+		// Directory buckets support none of the tags and settings applied below.
+		if item.Directory != nil {
+			err := createS3DirectoryBucket(ctx, svc, awsStringBucket, item.Directory)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error creating directory bucket",
+					"Could not create directory bucket "+awsStringBucket+": "+err.Error(),
+				)
+				return
+			}
+			plan.Buckets[index].Date = types.StringValue(time.Now().Format(time.RFC850))
+			plan.Buckets[index].AccelerateEndpoint = s3BucketAccelerateEndpoint(plan.Buckets[index])
+			continue
+		}
+
 		// Create input parameters for the CreateBucket operation
 
 		input := &s3.CreateBucketInput{
 
 			Bucket: aws.String(awsStringBucket),
 		}
+		if item.ObjectLockEnabled.ValueBool() {
+			input.ObjectLockEnabledForBucket = aws.Bool(true)
+		}
 
 		// Execute the CreateBucket operation
 
@@ -167,17 +370,55 @@ func (r *s3Resource) Create(ctx context.Context, req resource.CreateRequest, res
 
 		}
 
-		fmt.Printf("Bucket %s created successfully\n", item.Name)
-
-		plan.Buckets[index] = buckets{
+		if item.ObjectLockEnabled.ValueBool() && item.ObjectLock != nil {
+			err = putS3ObjectLockConfiguration(ctx, svc, awsStringBucket, item.ObjectLock)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error configuring object lock",
+					"Could not set default retention on bucket "+awsStringBucket+": "+err.Error(),
+				)
+				return
+			}
+		}
 
-			Name: types.StringValue(awsStringBucket),
+		err = applyS3BucketLogging(ctx, svc, awsStringBucket, nil, item.Logging)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error configuring access logging",
+				"Could not configure server access logging on bucket "+awsStringBucket+": "+err.Error(),
+			)
+			return
+		}
 
-			Date: types.StringValue(time.Now().Format(time.RFC850)),
+		if item.Acceleration.ValueBool() {
+			err = putS3BucketAcceleration(ctx, svc, awsStringBucket, true)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error configuring transfer acceleration",
+					"Could not enable Transfer Acceleration on bucket "+awsStringBucket+": "+err.Error(),
+				)
+				return
+			}
+		}
 
-			Tags: types.StringValue(tagValue),
+		if item.RequestPayer.ValueString() == string(awstypes.PayerRequester) {
+			err = putS3BucketRequestPayer(ctx, svc, awsStringBucket, item.RequestPayer.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error configuring requester pays",
+					"Could not set the request payer on bucket "+awsStringBucket+": "+err.Error(),
+				)
+				return
+			}
 		}
 
+		fmt.Printf("Bucket %s created successfully\n", item.Name)
+
+		plan.Buckets[index].Name = types.StringValue(awsStringBucket)
+		plan.Buckets[index].Date = types.StringValue(time.Now().Format(time.RFC850))
+		plan.Buckets[index].Tags = types.StringValue(tagValue)
+		plan.Buckets[index].AccelerateEndpoint = s3BucketAccelerateEndpoint(plan.Buckets[index])
+
 	}
 
 	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
@@ -210,7 +451,7 @@ func (r *s3Resource) Read(ctx context.Context, req resource.ReadRequest, resp *r
 
 	}
 
-	for _, item := range state.Buckets {
+	for index, item := range state.Buckets {
 
 		awsStringBucket := strings.Replace(item.Name.String(), "\"", "", -1)
 
@@ -221,7 +462,7 @@ func (r *s3Resource) Read(ctx context.Context, req resource.ReadRequest, resp *r
 			Bucket: aws.String(awsStringBucket),
 		}
 
-		_, err := svc.HeadBucket(ctx, params)
+		head, err := svc.HeadBucket(ctx, params)
 
 		if err != nil {
 
@@ -230,6 +471,58 @@ func (r *s3Resource) Read(ctx context.Context, req resource.ReadRequest, resp *r
 
 		}
 
+		if item.Directory != nil {
+			if zone := aws.ToString(head.BucketLocationName); zone != "" {
+				item.Directory.AvailabilityZoneID = types.StringValue(zone)
+			}
+			if head.BucketLocationType != "" {
+				item.Directory.LocationType = types.StringValue(string(head.BucketLocationType))
+			}
+			continue
+		}
+
+		lockEnabled, lock, err := readS3ObjectLockConfiguration(ctx, svc, awsStringBucket)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error reading object lock configuration",
+				"Could not read object lock configuration for bucket "+awsStringBucket+": "+err.Error(),
+			)
+			return
+		}
+		state.Buckets[index].ObjectLockEnabled = types.BoolValue(lockEnabled)
+		state.Buckets[index].ObjectLock = lock
+
+		logging, err := readS3BucketLogging(ctx, svc, awsStringBucket, item.Logging)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error reading access logging",
+				"Could not read server access logging for bucket "+awsStringBucket+": "+err.Error(),
+			)
+			return
+		}
+		state.Buckets[index].Logging = logging
+
+		accelerated, err := readS3BucketAcceleration(ctx, svc, awsStringBucket)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error reading transfer acceleration",
+				"Could not read Transfer Acceleration status for bucket "+awsStringBucket+": "+err.Error(),
+			)
+			return
+		}
+		state.Buckets[index].Acceleration = types.BoolValue(accelerated)
+		state.Buckets[index].AccelerateEndpoint = s3BucketAccelerateEndpoint(state.Buckets[index])
+
+		payer, err := readS3BucketRequestPayer(ctx, svc, awsStringBucket)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error reading requester pays",
+				"Could not read the request payer for bucket "+awsStringBucket+": "+err.Error(),
+			)
+			return
+		}
+		state.Buckets[index].RequestPayer = types.StringValue(payer)
+
 	}
 
 	// Set refreshed state
@@ -263,6 +556,23 @@ func (r *s3Resource) Update(ctx context.Context, req resource.UpdateRequest, res
 
 	}
 
+	var state s3ResourceModel
+
+	diags = req.State.Get(ctx, &state)
+
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+
+		return
+
+	}
+
+	priorBuckets := make(map[string]buckets, len(state.Buckets))
+	for _, item := range state.Buckets {
+		priorBuckets[item.Name.ValueString()] = item
+	}
+
 	plan.ID = types.StringValue(strconv.Itoa(1))
 
 	for index, item := range plan.Buckets {
@@ -273,6 +583,14 @@ func (r *s3Resource) Update(ctx context.Context, req resource.UpdateRequest, res
 
 		awsStringBucket := strings.Replace(item.Name.String(), "\"", "", -1)
 
+		// ModifyPlan rejects every setting a directory bucket does not support, which
+		// leaves nothing to update on one.
+		if item.Directory != nil {
+			plan.Buckets[index].Date = types.StringValue(time.Now().Format(time.RFC850))
+			plan.Buckets[index].AccelerateEndpoint = s3BucketAccelerateEndpoint(plan.Buckets[index])
+			continue
+		}
+
 		// Add tags
 
 		var tags []awstypes.Tag
@@ -304,15 +622,59 @@ func (r *s3Resource) Update(ctx context.Context, req resource.UpdateRequest, res
 
 		}
 
-		plan.Buckets[index] = buckets{
+		prior := priorBuckets[awsStringBucket]
+		if item.ObjectLockEnabled.ValueBool() && (item.ObjectLock != nil || prior.ObjectLock != nil) {
+			err = putS3ObjectLockConfiguration(ctx, svc, awsStringBucket, item.ObjectLock)
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error configuring object lock",
+					"Could not update default retention on bucket "+awsStringBucket+": "+err.Error(),
+				)
+				return
+			}
+		}
 
-			Name: types.StringValue(strings.Replace(awsStringBucket, "\"", "", -1)),
+		err = applyS3BucketLogging(ctx, svc, awsStringBucket, prior.Logging, item.Logging)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Error configuring access logging",
+				"Could not update server access logging on bucket "+awsStringBucket+": "+err.Error(),
+			)
+			return
+		}
 
-			Date: types.StringValue(time.Now().Format(time.RFC850)),
+		// Buckets new to the list start out with acceleration off and the owner paying.
+		if item.Acceleration.ValueBool() != prior.Acceleration.ValueBool() {
+			err = putS3BucketAcceleration(ctx, svc, awsStringBucket, item.Acceleration.ValueBool())
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error configuring transfer acceleration",
+					"Could not update Transfer Acceleration on bucket "+awsStringBucket+": "+err.Error(),
+				)
+				return
+			}
+		}
 
-			Tags: types.StringValue(strings.Replace(tagValue, "\"", "", -1)),
+		priorPayer := prior.RequestPayer.ValueString()
+		if priorPayer == "" {
+			priorPayer = string(awstypes.PayerBucketOwner)
+		}
+		if item.RequestPayer.ValueString() != priorPayer {
+			err = putS3BucketRequestPayer(ctx, svc, awsStringBucket, item.RequestPayer.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError(
+					"Error configuring requester pays",
+					"Could not update the request payer on bucket "+awsStringBucket+": "+err.Error(),
+				)
+				return
+			}
 		}
 
+		plan.Buckets[index].Name = types.StringValue(strings.Replace(awsStringBucket, "\"", "", -1))
+		plan.Buckets[index].Date = types.StringValue(time.Now().Format(time.RFC850))
+		plan.Buckets[index].Tags = types.StringValue(strings.Replace(tagValue, "\"", "", -1))
+		plan.Buckets[index].AccelerateEndpoint = s3BucketAccelerateEndpoint(plan.Buckets[index])
+
 	}
 
 	plan.Last_Updated = types.StringValue(time.Now().Format(time.RFC850))
@@ -350,6 +712,13 @@ func (r *s3Resource) Delete(ctx context.Context, req resource.DeleteRequest, res
 
 		svc := r.client.S3Client
 
+		if item.Logging != nil && item.Logging.AttachDeliveryPolicy.ValueBool() {
+			err := detachS3LogDeliveryPolicy(ctx, svc, item.Logging.TargetBucket.ValueString(), item.Name.ValueString())
+			if err != nil {
+				tflog.Error(ctx, fmt.Sprintf("failed to remove log delivery policy from %s: %v", item.Logging.TargetBucket.ValueString(), err), map[string]any{"success": false})
+			}
+		}
+
 		input := &s3.DeleteBucketInput{
 
 			Bucket: aws.String(strings.Replace(item.Name.String(), "\"", "", -1)),
@@ -0,0 +1,667 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/aws-sdk-go-v2/service/s3control"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Ensure the implementation satisfies the expected interfaces.
+var (
+	_ datasource.DataSource              = &s3SecurityReportDataSource{}
+	_ datasource.DataSourceWithConfigure = &s3SecurityReportDataSource{}
+)
+
+const (
+	s3SeverityCritical = "CRITICAL"
+	s3SeverityHigh     = "HIGH"
+	s3SeverityMedium   = "MEDIUM"
+	s3SeverityLow      = "LOW"
+)
+
+// s3Severities lists the finding severities from most to least severe.
+var s3Severities = []string{s3SeverityCritical, s3SeverityHigh, s3SeverityMedium, s3SeverityLow}
+
+// s3PublicGrantees are the ACL groups that make a bucket public.
+var s3PublicGrantees = map[string]bool{
+	"http://acs.amazonaws.com/groups/global/AllUsers":           true,
+	"http://acs.amazonaws.com/groups/global/AuthenticatedUsers": true,
+}
+
+// s3PostureCheck is one check of the security report. evaluate returns a non-empty detail
+// when the bucket fails the check.
+type s3PostureCheck struct {
+	ID          string
+	Severity    string
+	Title       string
+	Remediation string
+	evaluate    func(ctx context.Context, svc *s3.Client, bucket string, optFns ...func(*s3.Options)) (string, error)
+}
+
+// s3PostureChecks are the checks run against every bucket, in report order.
+var s3PostureChecks = []s3PostureCheck{
+	{
+		ID:          "public_acl",
+		Severity:    s3SeverityCritical,
+		Title:       "Bucket ACL grants access to everyone",
+		Remediation: "Remove the AllUsers and AuthenticatedUsers grants from the bucket ACL, or set object_ownership to BucketOwnerEnforced to disable ACLs.",
+		evaluate: func(ctx context.Context, svc *s3.Client, bucket string, optFns ...func(*s3.Options)) (string, error) {
+			out, err := svc.GetBucketAcl(ctx, &s3.GetBucketAclInput{Bucket: aws.String(bucket)}, optFns...)
+			if err != nil {
+				return "", err
+			}
+			var public []string
+			for _, grant := range out.Grants {
+				if grant.Grantee != nil && s3PublicGrantees[aws.ToString(grant.Grantee.URI)] {
+					public = append(public, string(grant.Permission)+" to "+aws.ToString(grant.Grantee.URI))
+				}
+			}
+			if len(public) == 0 {
+				return "", nil
+			}
+			return "The ACL grants " + strings.Join(public, ", ") + ".", nil
+		},
+	},
+	{
+		ID:          "public_policy",
+		Severity:    s3SeverityCritical,
+		Title:       "Bucket policy allows public access",
+		Remediation: "Restrict the Principal of the bucket policy's Allow statements to specific accounts or add conditions, and enable Block Public Access.",
+		evaluate: func(ctx context.Context, svc *s3.Client, bucket string, optFns ...func(*s3.Options)) (string, error) {
+			out, err := svc.GetBucketPolicyStatus(ctx, &s3.GetBucketPolicyStatusInput{Bucket: aws.String(bucket)}, optFns...)
+			if isS3ErrorCode(err, "NoSuchBucketPolicy") {
+				return "", nil
+			}
+			if err != nil {
+				return "", err
+			}
+			if out.PolicyStatus == nil || !aws.ToBool(out.PolicyStatus.IsPublic) {
+				return "", nil
+			}
+			return "S3 evaluates the bucket policy as public.", nil
+		},
+	},
+	{
+		ID:          "default_encryption",
+		Severity:    s3SeverityHigh,
+		Title:       "Default encryption is not configured",
+		Remediation: "Configure default encryption with SSE-S3 or SSE-KMS on the bucket.",
+		evaluate: func(ctx context.Context, svc *s3.Client, bucket string, optFns ...func(*s3.Options)) (string, error) {
+			out, err := svc.GetBucketEncryption(ctx, &s3.GetBucketEncryptionInput{Bucket: aws.String(bucket)}, optFns...)
+			if isS3ErrorCode(err, "ServerSideEncryptionConfigurationNotFoundError") {
+				return "The bucket has no default encryption configuration.", nil
+			}
+			if err != nil {
+				return "", err
+			}
+			if out.ServerSideEncryptionConfiguration == nil || len(out.ServerSideEncryptionConfiguration.Rules) == 0 {
+				return "The bucket has no default encryption rules.", nil
+			}
+			return "", nil
+		},
+	},
+	{
+		ID:          "versioning",
+		Severity:    s3SeverityMedium,
+		Title:       "Versioning is not enabled",
+		Remediation: "Enable versioning so overwritten and deleted objects can be recovered.",
+		evaluate: func(ctx context.Context, svc *s3.Client, bucket string, optFns ...func(*s3.Options)) (string, error) {
+			out, err := svc.GetBucketVersioning(ctx, &s3.GetBucketVersioningInput{Bucket: aws.String(bucket)}, optFns...)
+			if err != nil {
+				return "", err
+			}
+			switch out.Status {
+			case awstypes.BucketVersioningStatusEnabled:
+				return "", nil
+			case awstypes.BucketVersioningStatusSuspended:
+				return "Versioning is suspended.", nil
+			default:
+				return "Versioning has never been enabled.", nil
+			}
+		},
+	},
+	{
+		ID:          "block_public_access",
+		Severity:    s3SeverityHigh,
+		Title:       "Block Public Access is not fully enabled",
+		Remediation: "Turn on all four Block Public Access settings on the bucket or on the account.",
+		evaluate:    evaluateS3BlockPublicAccess(nil),
+	},
+	{
+		ID:          "tls_only_policy",
+		Severity:    s3SeverityMedium,
+		Title:       "Bucket policy does not require TLS",
+		Remediation: "Add a bucket policy statement denying s3:* to every principal when aws:SecureTransport is false.",
+		evaluate: func(ctx context.Context, svc *s3.Client, bucket string, optFns ...func(*s3.Options)) (string, error) {
+			out, err := svc.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{Bucket: aws.String(bucket)}, optFns...)
+			if isS3ErrorCode(err, "NoSuchBucketPolicy") {
+				return "The bucket has no policy.", nil
+			}
+			if err != nil {
+				return "", err
+			}
+			options := svc.Options()
+			for _, fn := range optFns {
+				fn(&options)
+			}
+			if hasS3TLSOnlyStatement(aws.ToString(out.Policy), s3BucketARN(bucket, options.Region)) {
+				return "", nil
+			}
+			return "No policy statement denies requests made without TLS.", nil
+		},
+	},
+	{
+		ID:          "access_logging",
+		Severity:    s3SeverityLow,
+		Title:       "Server access logging is disabled",
+		Remediation: "Set logging on the bucket to deliver server access logs to a dedicated log bucket.",
+		evaluate: func(ctx context.Context, svc *s3.Client, bucket string, optFns ...func(*s3.Options)) (string, error) {
+			out, err := svc.GetBucketLogging(ctx, &s3.GetBucketLoggingInput{Bucket: aws.String(bucket)}, optFns...)
+			if err != nil {
+				return "", err
+			}
+			if out.LoggingEnabled != nil {
+				return "", nil
+			}
+			return "The bucket does not deliver server access logs.", nil
+		},
+	},
+	{
+		ID:          "object_ownership",
+		Severity:    s3SeverityMedium,
+		Title:       "Object Ownership is not BucketOwnerEnforced",
+		Remediation: "Set Object Ownership to BucketOwnerEnforced so ACLs are disabled and the bucket owner owns every object.",
+		evaluate: func(ctx context.Context, svc *s3.Client, bucket string, optFns ...func(*s3.Options)) (string, error) {
+			out, err := svc.GetBucketOwnershipControls(ctx, &s3.GetBucketOwnershipControlsInput{Bucket: aws.String(bucket)}, optFns...)
+			if isS3ErrorCode(err, "OwnershipControlsNotFoundError") {
+				return "The bucket has no ownership controls, so ACLs are in effect.", nil
+			}
+			if err != nil {
+				return "", err
+			}
+			if out.OwnershipControls != nil {
+				for _, rule := range out.OwnershipControls.Rules {
+					if rule.ObjectOwnership == awstypes.ObjectOwnershipBucketOwnerEnforced {
+						return "", nil
+					}
+					return "Object Ownership is " + string(rule.ObjectOwnership) + ".", nil
+				}
+			}
+			return "The bucket has no ownership controls, so ACLs are in effect.", nil
+		},
+	},
+}
+
+// NewS3SecurityReportDataSource is a helper function to simplify the provider implementation.
+func NewS3SecurityReportDataSource() datasource.DataSource {
+	return &s3SecurityReportDataSource{}
+}
+
+// s3SecurityReportDataSource checks buckets against common security settings.
+type s3SecurityReportDataSource struct {
+	client *ClientS3
+}
+
+type s3SecurityReportDataSourceModel struct {
+	Buckets         []string                 `tfsdk:"buckets"`
+	NameRegex       types.String             `tfsdk:"name_regex"`
+	Prefix          types.String             `tfsdk:"prefix"`
+	Checks          []string                 `tfsdk:"checks"`
+	ScannedBuckets  []string                 `tfsdk:"scanned_buckets"`
+	Findings        []s3SecurityFindingModel `tfsdk:"findings"`
+	SeverityCounts  map[string]int64         `tfsdk:"severity_counts"`
+	HighestSeverity types.String             `tfsdk:"highest_severity"`
+}
+
+type s3SecurityFindingModel struct {
+	Bucket      types.String `tfsdk:"bucket"`
+	Check       types.String `tfsdk:"check"`
+	Severity    types.String `tfsdk:"severity"`
+	Title       types.String `tfsdk:"title"`
+	Detail      types.String `tfsdk:"detail"`
+	Remediation types.String `tfsdk:"remediation"`
+}
+
+// Metadata returns the data source type name.
+func (d *s3SecurityReportDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_s3_security_report"
+}
+
+// Schema defines the schema for the data source.
+func (d *s3SecurityReportDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	checkIDs := make([]string, 0, len(s3PostureChecks))
+	for _, check := range s3PostureChecks {
+		checkIDs = append(checkIDs, check.ID)
+	}
+
+	resp.Schema = schema.Schema{
+		Description: "Checks buckets for public access, missing encryption, versioning, Block Public Access, " +
+			"TLS-only policy, access logging and Object Ownership, and reports each failure as a finding.",
+		Attributes: map[string]schema.Attribute{
+			"buckets": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Buckets to check. All buckets of the account are checked when unset.",
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only check buckets whose name matches this regular expression.",
+			},
+			"prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Only check buckets whose name starts with this prefix.",
+			},
+			"checks": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Checks to run, out of " + strings.Join(checkIDs, ", ") + ". All are run when unset.",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.OneOf(checkIDs...)),
+				},
+			},
+			"scanned_buckets": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+			"findings": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Failed checks, ordered by bucket and check.",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"bucket": schema.StringAttribute{
+							Computed: true,
+						},
+						"check": schema.StringAttribute{
+							Computed: true,
+						},
+						"severity": schema.StringAttribute{
+							Computed:    true,
+							Description: strings.Join(s3Severities, ", ") + ".",
+						},
+						"title": schema.StringAttribute{
+							Computed: true,
+						},
+						"detail": schema.StringAttribute{
+							Computed: true,
+						},
+						"remediation": schema.StringAttribute{
+							Computed: true,
+						},
+					},
+				},
+			},
+			"severity_counts": schema.MapAttribute{
+				Computed:    true,
+				ElementType: types.Int64Type,
+				Description: "Number of findings per severity, with every severity present.",
+			},
+			"highest_severity": schema.StringAttribute{
+				Computed:    true,
+				Description: "Most severe finding, null when there are none.",
+			},
+		},
+	}
+}
+
+// Read refreshes the Terraform state with the latest data.
+func (d *s3SecurityReportDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state s3SecurityReportDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var nameRegex *regexp.Regexp
+	if !state.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(state.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid Regular Expression", err.Error())
+			return
+		}
+	}
+
+	svc := d.client.S3Client
+	regions := map[string]string{}
+	if state.Buckets != nil {
+		for _, bucket := range state.Buckets {
+			regions[bucket] = ""
+		}
+	} else {
+		paginator := s3.NewListBucketsPaginator(svc, &s3.ListBucketsInput{Prefix: state.Prefix.ValueStringPointer()}, func(o *s3.ListBucketsPaginatorOptions) {
+			o.Limit = 1000
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				resp.Diagnostics.AddError("unable to list buckets", "Could not list buckets: "+err.Error())
+				return
+			}
+			for _, bucket := range page.Buckets {
+				regions[aws.ToString(bucket.Name)] = aws.ToString(bucket.BucketRegion)
+			}
+		}
+	}
+
+	buckets := make([]string, 0, len(regions))
+	for bucket := range regions {
+		if !strings.HasPrefix(bucket, state.Prefix.ValueString()) {
+			continue
+		}
+		if nameRegex != nil && !nameRegex.MatchString(bucket) {
+			continue
+		}
+		buckets = append(buckets, bucket)
+	}
+	sort.Strings(buckets)
+
+	checks := s3PostureChecks
+	if state.Checks != nil {
+		selected := map[string]bool{}
+		for _, id := range state.Checks {
+			selected[id] = true
+		}
+		checks = nil
+		for _, check := range s3PostureChecks {
+			if selected[check.ID] {
+				checks = append(checks, check)
+			}
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		findings = map[string][]s3SecurityFindingModel{}
+		denied   []string
+	)
+	for index, check := range checks {
+		if check.ID != "block_public_access" {
+			continue
+		}
+		account, err := d.readS3AccountPublicAccessBlock(ctx)
+		if isS3ErrorCode(err, "AccessDenied") {
+			denied = append(denied, "account: block_public_access")
+		} else if err != nil {
+			resp.Diagnostics.AddError("unable to check bucket security", "Could not read the account's Block Public Access configuration: "+err.Error())
+			return
+		}
+		// checks may share its backing array with s3PostureChecks.
+		checks = append([]s3PostureCheck{}, checks...)
+		checks[index].evaluate = evaluateS3BlockPublicAccess(account)
+	}
+	err := forEachConcurrently(ctx, 10, buckets, func(ctx context.Context, bucket string) error {
+		region := regions[bucket]
+		if region == "" {
+			var err error
+			if region, err = readS3BucketRegion(ctx, svc, bucket); err != nil {
+				return fmt.Errorf("%s: %w", bucket, err)
+			}
+		}
+		inRegion := func(o *s3.Options) { o.Region = region }
+
+		var bucketFindings []s3SecurityFindingModel
+		for _, check := range checks {
+			detail, err := check.evaluate(ctx, svc, bucket, inRegion)
+			if isS3ErrorCode(err, "AccessDenied") {
+				mu.Lock()
+				denied = append(denied, bucket+": "+check.ID)
+				mu.Unlock()
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %s: %w", bucket, check.ID, err)
+			}
+			if detail == "" {
+				continue
+			}
+			bucketFindings = append(bucketFindings, s3SecurityFindingModel{
+				Bucket:      types.StringValue(bucket),
+				Check:       types.StringValue(check.ID),
+				Severity:    types.StringValue(check.Severity),
+				Title:       types.StringValue(check.Title),
+				Detail:      types.StringValue(detail),
+				Remediation: types.StringValue(check.Remediation),
+			})
+		}
+		mu.Lock()
+		findings[bucket] = bucketFindings
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("unable to check bucket security", err.Error())
+		return
+	}
+	if len(denied) > 0 {
+		sort.Strings(denied)
+		resp.Diagnostics.AddWarning(
+			"Some checks could not be run",
+			"Access was denied for: "+strings.Join(denied, ", ")+". These checks are missing from the findings.",
+		)
+	}
+
+	state.ScannedBuckets = buckets
+	state.Findings = []s3SecurityFindingModel{}
+	for _, bucket := range buckets {
+		state.Findings = append(state.Findings, findings[bucket]...)
+	}
+	state.SeverityCounts, state.HighestSeverity = summarizeS3Findings(state.Findings)
+
+	diags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// summarizeS3Findings counts findings per severity and returns the most severe one present.
+func summarizeS3Findings(findings []s3SecurityFindingModel) (map[string]int64, types.String) {
+	counts := map[string]int64{}
+	for _, severity := range s3Severities {
+		counts[severity] = 0
+	}
+	for _, finding := range findings {
+		counts[finding.Severity.ValueString()]++
+	}
+	for _, severity := range s3Severities {
+		if counts[severity] > 0 {
+			return counts, types.StringValue(severity)
+		}
+	}
+	return counts, types.StringNull()
+}
+
+// evaluateS3BlockPublicAccess returns the block_public_access check for an account with the
+// given account-level configuration, nil when the account has none. A setting counts as
+// enabled when either the account or the bucket enables it.
+func evaluateS3BlockPublicAccess(account *awstypes.PublicAccessBlockConfiguration) func(ctx context.Context, svc *s3.Client, bucket string, optFns ...func(*s3.Options)) (string, error) {
+	return func(ctx context.Context, svc *s3.Client, bucket string, optFns ...func(*s3.Options)) (string, error) {
+		out, err := svc.GetPublicAccessBlock(ctx, &s3.GetPublicAccessBlockInput{Bucket: aws.String(bucket)}, optFns...)
+		if err != nil && !isS3ErrorCode(err, "NoSuchPublicAccessBlockConfiguration") {
+			return "", err
+		}
+		var block *awstypes.PublicAccessBlockConfiguration
+		if err == nil {
+			block = out.PublicAccessBlockConfiguration
+		}
+		if block == nil && account == nil {
+			return "Neither the bucket nor the account has a Block Public Access configuration.", nil
+		}
+		off := disabledS3PublicAccessBlocks(block, account)
+		if len(off) == 0 {
+			return "", nil
+		}
+		return "Disabled on both the bucket and the account: " + strings.Join(off, ", ") + ".", nil
+	}
+}
+
+// disabledS3PublicAccessBlocks lists, sorted, the Block Public Access settings that none of
+// the given configurations enables. Nil configurations enable nothing.
+func disabledS3PublicAccessBlocks(configs ...*awstypes.PublicAccessBlockConfiguration) []string {
+	enabled := map[string]bool{}
+	for _, config := range configs {
+		if config == nil {
+			continue
+		}
+		enabled["BlockPublicAcls"] = enabled["BlockPublicAcls"] || aws.ToBool(config.BlockPublicAcls)
+		enabled["BlockPublicPolicy"] = enabled["BlockPublicPolicy"] || aws.ToBool(config.BlockPublicPolicy)
+		enabled["IgnorePublicAcls"] = enabled["IgnorePublicAcls"] || aws.ToBool(config.IgnorePublicAcls)
+		enabled["RestrictPublicBuckets"] = enabled["RestrictPublicBuckets"] || aws.ToBool(config.RestrictPublicBuckets)
+	}
+	var off []string
+	for _, name := range []string{"BlockPublicAcls", "BlockPublicPolicy", "IgnorePublicAcls", "RestrictPublicBuckets"} {
+		if !enabled[name] {
+			off = append(off, name)
+		}
+	}
+	return off
+}
+
+// readS3AccountPublicAccessBlock reads the account-level Block Public Access configuration,
+// returning nil when the account has none.
+func (d *s3SecurityReportDataSource) readS3AccountPublicAccessBlock(ctx context.Context) (*awstypes.PublicAccessBlockConfiguration, error) {
+	accountID, err := d.client.accountID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out, err := d.client.S3ControlClient.GetPublicAccessBlock(ctx, &s3control.GetPublicAccessBlockInput{AccountId: aws.String(accountID)})
+	if isS3ErrorCode(err, "NoSuchPublicAccessBlockConfiguration") {
+		return nil, nil
+	}
+	if err != nil || out.PublicAccessBlockConfiguration == nil {
+		return nil, err
+	}
+	account := out.PublicAccessBlockConfiguration
+	return &awstypes.PublicAccessBlockConfiguration{
+		BlockPublicAcls:       account.BlockPublicAcls,
+		BlockPublicPolicy:     account.BlockPublicPolicy,
+		IgnorePublicAcls:      account.IgnorePublicAcls,
+		RestrictPublicBuckets: account.RestrictPublicBuckets,
+	}, nil
+}
+
+// hasS3TLSOnlyStatement reports whether a bucket policy denies requests that are not sent
+// over TLS, that is a Deny statement of s3:* for every principal on the bucket and its
+// objects, conditioned on aws:SecureTransport being false.
+func hasS3TLSOnlyStatement(policyJSON, bucketARN string) bool {
+	var policy map[string]any
+	if err := json.Unmarshal([]byte(policyJSON), &policy); err != nil {
+		return false
+	}
+
+	var statements []any
+	switch existing := policy["Statement"].(type) {
+	case []any:
+		statements = existing
+	case map[string]any:
+		statements = []any{existing}
+	}
+
+	for _, item := range statements {
+		statement, ok := item.(map[string]any)
+		if !ok || statement["Effect"] != "Deny" || !isS3PolicyEveryone(statement["Principal"]) {
+			continue
+		}
+		actions := s3PolicyStrings(statement["Action"])
+		if !slices.Contains(actions, "*") && !slices.Contains(actions, "s3:*") {
+			continue
+		}
+		resources := s3PolicyStrings(statement["Resource"])
+		if !s3PolicyPatternsMatch(resources, bucketARN) || !s3PolicyPatternsMatch(resources, bucketARN+"/*") {
+			continue
+		}
+		condition, _ := statement["Condition"].(map[string]any)
+		boolCondition, _ := condition["Bool"].(map[string]any)
+		if slices.ContainsFunc(s3PolicyStrings(boolCondition["aws:SecureTransport"]), func(value string) bool {
+			return strings.EqualFold(value, "false")
+		}) {
+			return true
+		}
+	}
+	return false
+}
+
+// isS3PolicyEveryone reports whether a statement principal is "*" or {"AWS": "*"}.
+func isS3PolicyEveryone(principal any) bool {
+	if principal == "*" {
+		return true
+	}
+	principals, _ := principal.(map[string]any)
+	return slices.Contains(s3PolicyStrings(principals["AWS"]), "*")
+}
+
+// s3PolicyStrings returns a policy element that may be a single value or a list as a list
+// of strings.
+func s3PolicyStrings(element any) []string {
+	var values []any
+	switch element := element.(type) {
+	case []any:
+		values = element
+	case nil:
+	default:
+		values = []any{element}
+	}
+	out := make([]string, 0, len(values))
+	for _, value := range values {
+		out = append(out, fmt.Sprint(value))
+	}
+	return out
+}
+
+// s3PolicyPatternsMatch reports whether one of the resource patterns, which may use the * and
+// ? wildcards, matches resource.
+func s3PolicyPatternsMatch(patterns []string, resource string) bool {
+	for _, pattern := range patterns {
+		var expr strings.Builder
+		expr.WriteString("^")
+		for _, c := range pattern {
+			switch c {
+			case '*':
+				expr.WriteString(".*")
+			case '?':
+				expr.WriteString(".")
+			default:
+				expr.WriteString(regexp.QuoteMeta(string(c)))
+			}
+		}
+		expr.WriteString("$")
+		if regexp.MustCompile(expr.String()).MatchString(resource) {
+			return true
+		}
+	}
+	return false
+}
+
+// Configure adds the provider configured client to the data source.
+func (d *s3SecurityReportDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// Add a nil check when handling ProviderData because Terraform
+	// sets that data after it calls the ConfigureProvider RPC.
+	if req.ProviderData == nil {
+		return
+	}
+
+	client, ok := req.ProviderData.(*ClientS3)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *ClientS3, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.client = client
+}
@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestHasS3TLSOnlyStatement(t *testing.T) {
+	const (
+		bucketARN = "arn:aws:s3:::logs"
+		resources = `"Resource":["arn:aws:s3:::logs","arn:aws:s3:::logs/*"]`
+		insecure  = `"Condition":{"Bool":{"aws:SecureTransport":"false"}}`
+	)
+	cases := map[string]bool{
+		`{"Statement":[{"Effect":"Deny","Principal":"*","Action":"s3:*",` + resources + `,` + insecure + `}]}`:                                           true,
+		`{"Statement":{"Effect":"Deny","Principal":{"AWS":"*"},"Action":["s3:*"],` + resources + `,"Condition":{"Bool":{"aws:SecureTransport":false}}}}`: true,
+		`{"Statement":[{"Effect":"Deny","Principal":"*","Action":"*","Resource":"arn:aws:s3:::log*",` + insecure + `}]}`:                                 true,
+		`{"Statement":[{"Effect":"Allow","Principal":"*","Action":"s3:*",` + resources + `,` + insecure + `}]}`:                                          false,
+		`{"Statement":[{"Effect":"Deny","Principal":"*","Action":"s3:*",` + resources + `,"Condition":{"Bool":{"aws:SecureTransport":"true"}}}]}`:        false,
+		`{"Statement":[{"Effect":"Deny","Principal":"*","Action":"s3:GetObject",` + resources + `,` + insecure + `}]}`:                                   false,
+		`{"Statement":[{"Effect":"Deny","Principal":{"AWS":"arn:aws:iam::123456789012:root"},"Action":"s3:*",` + resources + `,` + insecure + `}]}`:      false,
+		`{"Statement":[{"Effect":"Deny","Action":"s3:*",` + resources + `,` + insecure + `}]}`:                                                           false,
+		`{"Statement":[{"Effect":"Deny","Principal":"*","Action":"s3:*","Resource":"arn:aws:s3:::logs/private/*",` + insecure + `}]}`:                    false,
+		`{"Statement":[{"Effect":"Deny","Principal":"*","Action":"s3:*","Resource":"arn:aws:s3:::logs",` + insecure + `}]}`:                              false,
+		`{"Statement":[{"Effect":"Deny","Principal":"*","Action":"s3:*","Resource":["arn:aws:s3:::other","arn:aws:s3:::other/*"],` + insecure + `}]}`:    false,
+		`{"Statement":[{"Effect":"Allow","Action":"s3:GetObject"}]}`:                                                                                     false,
+		`not json`: false,
+	}
+	for policy, want := range cases {
+		if got := hasS3TLSOnlyStatement(policy, bucketARN); got != want {
+			t.Errorf("hasS3TLSOnlyStatement(%s) = %v, want %v", policy, got, want)
+		}
+	}
+}
+
+func TestSummarizeS3Findings(t *testing.T) {
+	counts, highest := summarizeS3Findings(nil)
+	if !highest.IsNull() || counts[s3SeverityCritical] != 0 || len(counts) != len(s3Severities) {
+		t.Errorf("empty report = %v %v", counts, highest)
+	}
+
+	counts, highest = summarizeS3Findings([]s3SecurityFindingModel{
+		{Severity: types.StringValue(s3SeverityLow)},
+		{Severity: types.StringValue(s3SeverityHigh)},
+		{Severity: types.StringValue(s3SeverityLow)},
+	})
+	if highest.ValueString() != s3SeverityHigh || counts[s3SeverityLow] != 2 || counts[s3SeverityHigh] != 1 {
+		t.Errorf("report = %v %v", counts, highest)
+	}
+}
+
+func TestDisabledS3PublicAccessBlocks(t *testing.T) {
+	bucket := &awstypes.PublicAccessBlockConfiguration{BlockPublicAcls: aws.Bool(true), IgnorePublicAcls: aws.Bool(true)}
+	account := &awstypes.PublicAccessBlockConfiguration{
+		BlockPublicAcls:       aws.Bool(true),
+		BlockPublicPolicy:     aws.Bool(true),
+		IgnorePublicAcls:      aws.Bool(true),
+		RestrictPublicBuckets: aws.Bool(true),
+	}
+
+	if off := disabledS3PublicAccessBlocks(bucket, account); len(off) != 0 {
+		t.Errorf("account-level settings not counted: %v", off)
+	}
+	if off := disabledS3PublicAccessBlocks(bucket, nil); !reflect.DeepEqual(off, []string{"BlockPublicPolicy", "RestrictPublicBuckets"}) {
+		t.Errorf("bucket only = %v", off)
+	}
+	if off := disabledS3PublicAccessBlocks(nil, nil); len(off) != 4 {
+		t.Errorf("no configuration = %v", off)
+	}
+}
@@ -0,0 +1,377 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	awstypes "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// s3ObjectUpload carries the object headers and settings applied when an object is uploaded.
+// Empty fields are left for S3 to default.
+type s3ObjectUpload struct {
+	Bucket               string
+	Key                  string
+	ContentType          string
+	CacheControl         string
+	ContentDisposition   string
+	Metadata             map[string]string
+	StorageClass         string
+	ServerSideEncryption string
+	KMSKeyID             string
+	Tags                 map[string]string
+}
+
+// s3UploadResult is what S3 reports back about a stored object.
+type s3UploadResult struct {
+	ETag      string
+	VersionID string
+	// ChecksumSHA256 is the base64 SHA-256 checksum S3 computed over the upload.
+	ChecksumSHA256 string
+}
+
+// putS3Object uploads body in a single PutObject request, asking S3 to verify and store a
+// SHA-256 checksum of the content.
+func putS3Object(ctx context.Context, svc *s3.Client, upload s3ObjectUpload, body io.ReadSeeker) (s3UploadResult, error) {
+	input := &s3.PutObjectInput{
+		Bucket:            aws.String(upload.Bucket),
+		Key:               aws.String(upload.Key),
+		Body:              body,
+		ChecksumAlgorithm: awstypes.ChecksumAlgorithmSha256,
+		Metadata:          upload.Metadata,
+	}
+	if upload.ContentType != "" {
+		input.ContentType = aws.String(upload.ContentType)
+	}
+	if upload.CacheControl != "" {
+		input.CacheControl = aws.String(upload.CacheControl)
+	}
+	if upload.ContentDisposition != "" {
+		input.ContentDisposition = aws.String(upload.ContentDisposition)
+	}
+	if upload.StorageClass != "" {
+		input.StorageClass = awstypes.StorageClass(upload.StorageClass)
+	}
+	if upload.ServerSideEncryption != "" {
+		input.ServerSideEncryption = awstypes.ServerSideEncryption(upload.ServerSideEncryption)
+	}
+	if upload.KMSKeyID != "" {
+		input.SSEKMSKeyId = aws.String(upload.KMSKeyID)
+	}
+	if len(upload.Tags) > 0 {
+		input.Tagging = aws.String(encodeS3Tagging(upload.Tags))
+	}
+
+	out, err := svc.PutObject(ctx, input)
+	if err != nil {
+		return s3UploadResult{}, err
+	}
+	return s3UploadResult{
+		ETag:           aws.ToString(out.ETag),
+		VersionID:      aws.ToString(out.VersionId),
+		ChecksumSHA256: aws.ToString(out.ChecksumSHA256),
+	}, nil
+}
+
+// encodeS3Tagging renders tags in the URL query form the x-amz-tagging header expects.
+func encodeS3Tagging(tags map[string]string) string {
+	values := url.Values{}
+	for key, value := range tags {
+		values.Set(key, value)
+	}
+	return values.Encode()
+}
+
+// sha256File hashes a local file without reading it fully into memory and returns the
+// lowercase hex digest.
+func sha256File(name string) (string, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// sha256Bytes returns the lowercase hex SHA-256 digest of data.
+func sha256Bytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// s3ChecksumToHex converts a base64 checksum as returned by S3 into lowercase hex, so it can
+// be compared with digests computed locally. Composite multipart checksums ("<digest>-<parts>")
+// and malformed values yield an empty string.
+func s3ChecksumToHex(checksum string) string {
+	raw, err := base64.StdEncoding.DecodeString(checksum)
+	if err != nil {
+		return ""
+	}
+	return hex.EncodeToString(raw)
+}
+
+// detectContentType guesses a MIME type from the file extension of name, falling back to
+// sniffing the first bytes of the content.
+func detectContentType(name string, head []byte) string {
+	if byExtension := mime.TypeByExtension(filepath.Ext(name)); byExtension != "" {
+		return byExtension
+	}
+	return http.DetectContentType(head)
+}
+
+// detectFileContentType is detectContentType for a local file.
+func detectFileContentType(name string) (string, error) {
+	if byExtension := mime.TypeByExtension(filepath.Ext(name)); byExtension != "" {
+		return byExtension, nil
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	return http.DetectContentType(head[:n]), nil
+}
+
+const (
+	s3MinPartSize = 5 * 1024 * 1024
+	s3MaxParts    = 10000
+)
+
+// s3MultipartOptions controls how a large object is split and how many parts are sent at once.
+type s3MultipartOptions struct {
+	PartSize    int64
+	Concurrency int
+}
+
+// effectivePartSize grows the configured part size when an object of the given size would
+// otherwise need more parts than S3 allows.
+func (o s3MultipartOptions) effectivePartSize(size int64) int64 {
+	partSize := o.PartSize
+	if partSize < s3MinPartSize {
+		partSize = s3MinPartSize
+	}
+	if minimum := (size + s3MaxParts - 1) / s3MaxParts; partSize < minimum {
+		const mib = 1024 * 1024
+		partSize = (minimum + mib - 1) / mib * mib
+	}
+	return partSize
+}
+
+// multipartUploadS3Object uploads size bytes of source as a multipart upload, sending up to
+// Concurrency parts in parallel. Every part is read straight from source through its own
+// section reader, so memory use does not grow with the object size. If any part fails the
+// upload is aborted so no orphaned parts are left behind.
+func multipartUploadS3Object(ctx context.Context, svc *s3.Client, upload s3ObjectUpload, source io.ReaderAt, size int64, opts s3MultipartOptions) (s3UploadResult, error) {
+	input := &s3.CreateMultipartUploadInput{
+		Bucket:            aws.String(upload.Bucket),
+		Key:               aws.String(upload.Key),
+		ChecksumAlgorithm: awstypes.ChecksumAlgorithmSha256,
+		Metadata:          upload.Metadata,
+	}
+	if upload.ContentType != "" {
+		input.ContentType = aws.String(upload.ContentType)
+	}
+	if upload.CacheControl != "" {
+		input.CacheControl = aws.String(upload.CacheControl)
+	}
+	if upload.ContentDisposition != "" {
+		input.ContentDisposition = aws.String(upload.ContentDisposition)
+	}
+	if upload.StorageClass != "" {
+		input.StorageClass = awstypes.StorageClass(upload.StorageClass)
+	}
+	if upload.ServerSideEncryption != "" {
+		input.ServerSideEncryption = awstypes.ServerSideEncryption(upload.ServerSideEncryption)
+	}
+	if upload.KMSKeyID != "" {
+		input.SSEKMSKeyId = aws.String(upload.KMSKeyID)
+	}
+	if len(upload.Tags) > 0 {
+		input.Tagging = aws.String(encodeS3Tagging(upload.Tags))
+	}
+
+	created, err := svc.CreateMultipartUpload(ctx, input)
+	if err != nil {
+		return s3UploadResult{}, err
+	}
+	uploadID := created.UploadId
+
+	abort := func(cause error) (s3UploadResult, error) {
+		// The upload context may already be cancelled; aborting must still go through.
+		_, abortErr := svc.AbortMultipartUpload(context.WithoutCancel(ctx), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(upload.Bucket),
+			Key:      aws.String(upload.Key),
+			UploadId: uploadID,
+		})
+		if abortErr != nil {
+			return s3UploadResult{}, fmt.Errorf("%w (aborting upload %s also failed: %s)", cause, aws.ToString(uploadID), abortErr)
+		}
+		return s3UploadResult{}, cause
+	}
+
+	partSize := opts.effectivePartSize(size)
+	partCount := int((size + partSize - 1) / partSize)
+	if partCount == 0 {
+		partCount = 1
+	}
+	numbers := make([]int, partCount)
+	for index := range numbers {
+		numbers[index] = index + 1
+	}
+	parts := make([]awstypes.CompletedPart, partCount)
+	err = forEachConcurrently(ctx, opts.Concurrency, numbers, func(ctx context.Context, number int) error {
+		offset := int64(number-1) * partSize
+		length := min(partSize, size-offset)
+		out, err := svc.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:            aws.String(upload.Bucket),
+			Key:               aws.String(upload.Key),
+			UploadId:          uploadID,
+			PartNumber:        aws.Int32(int32(number)),
+			Body:              io.NewSectionReader(source, offset, length),
+			ContentLength:     aws.Int64(length),
+			ChecksumAlgorithm: awstypes.ChecksumAlgorithmSha256,
+		})
+		if err != nil {
+			return fmt.Errorf("uploading part %d of %d: %w", number, partCount, err)
+		}
+		parts[number-1] = awstypes.CompletedPart{
+			ETag:           out.ETag,
+			PartNumber:     aws.Int32(int32(number)),
+			ChecksumSHA256: out.ChecksumSHA256,
+		}
+		return nil
+	})
+	if err != nil {
+		return abort(err)
+	}
+
+	completed, err := svc.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(upload.Bucket),
+		Key:             aws.String(upload.Key),
+		UploadId:        uploadID,
+		MultipartUpload: &awstypes.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		return abort(err)
+	}
+	return s3UploadResult{
+		ETag:           aws.ToString(completed.ETag),
+		VersionID:      aws.ToString(completed.VersionId),
+		ChecksumSHA256: aws.ToString(completed.ChecksumSHA256),
+	}, nil
+}
+
+// uploadS3File uploads a local file in one request, or in parallel parts when it is larger
+// than a single part.
+func uploadS3File(ctx context.Context, svc *s3.Client, upload s3ObjectUpload, name string, opts s3MultipartOptions) (s3UploadResult, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return s3UploadResult{}, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return s3UploadResult{}, err
+	}
+
+	if info.Size() > opts.effectivePartSize(info.Size()) {
+		return multipartUploadS3Object(ctx, svc, upload, file, info.Size(), opts)
+	}
+	return putS3Object(ctx, svc, upload, file)
+}
+
+// s3SourceDigest describes a local file the way S3 will checksum it.
+type s3SourceDigest struct {
+	// SHA256 is the lowercase hex digest of the whole file.
+	SHA256 string
+	// Checksum is the checksum S3 will report for the object: the base64 digest of the file
+	// for a single-part upload, or the composite "<base64 digest of part digests>-<parts>"
+	// for a multipart upload.
+	Checksum string
+	Size     int64
+}
+
+// digestS3Source hashes a local file in one streaming pass, producing both the whole-file
+// digest and the checksum S3 will compute when the file is uploaded with opts.
+func digestS3Source(name string, opts s3MultipartOptions) (s3SourceDigest, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return s3SourceDigest{}, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return s3SourceDigest{}, err
+	}
+	size := info.Size()
+	partSize := opts.effectivePartSize(size)
+
+	whole := sha256.New()
+	if size <= partSize {
+		if _, err := io.Copy(whole, file); err != nil {
+			return s3SourceDigest{}, err
+		}
+		sum := whole.Sum(nil)
+		return s3SourceDigest{
+			SHA256:   hex.EncodeToString(sum),
+			Checksum: base64.StdEncoding.EncodeToString(sum),
+			Size:     size,
+		}, nil
+	}
+
+	composite := sha256.New()
+	parts := 0
+	for offset := int64(0); offset < size; offset += partSize {
+		part := sha256.New()
+		if _, err := io.Copy(io.MultiWriter(whole, part), io.NewSectionReader(file, offset, partSize)); err != nil {
+			return s3SourceDigest{}, err
+		}
+		composite.Write(part.Sum(nil))
+		parts++
+	}
+	return s3SourceDigest{
+		SHA256:   hex.EncodeToString(whole.Sum(nil)),
+		Checksum: base64.StdEncoding.EncodeToString(composite.Sum(nil)) + "-" + strconv.Itoa(parts),
+		Size:     size,
+	}, nil
+}
+
+// sameS3Checksum compares two S3 checksums, ignoring the "-<parts>" suffix that only some
+// S3 responses include on composite checksums.
+func sameS3Checksum(a, b string) bool {
+	trim := func(checksum string) string {
+		if index := strings.LastIndex(checksum, "-"); index > 0 {
+			if _, err := strconv.Atoi(checksum[index+1:]); err == nil {
+				return checksum[:index]
+			}
+		}
+		return checksum
+	}
+	return trim(a) == trim(b)
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEffectivePartSize(t *testing.T) {
+	const mib = 1024 * 1024
+
+	cases := []struct {
+		name string
+		opts s3MultipartOptions
+		size int64
+		want int64
+	}{
+		{"configured size", s3MultipartOptions{PartSize: 16 * mib}, 100 * mib, 16 * mib},
+		{"raised to S3 minimum", s3MultipartOptions{PartSize: mib}, 100 * mib, 5 * mib},
+		{"raised to fit part limit", s3MultipartOptions{PartSize: 5 * mib}, 100000 * mib, 10 * mib},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.opts.effectivePartSize(tc.size); got != tc.want {
+				t.Errorf("got %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestDigestS3Source(t *testing.T) {
+	const partSize = 5 * 1024 * 1024
+	data := bytes.Repeat([]byte("xsynchco"), (2*partSize+1024)/8)
+	name := filepath.Join(t.TempDir(), "artifact.bin")
+	if err := os.WriteFile(name, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	whole := sha256.Sum256(data)
+
+	single, err := digestS3Source(name, s3MultipartOptions{PartSize: 3 * partSize})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if single.SHA256 != hex.EncodeToString(whole[:]) {
+		t.Errorf("single part SHA256 = %s", single.SHA256)
+	}
+	if single.Checksum != base64.StdEncoding.EncodeToString(whole[:]) {
+		t.Errorf("single part checksum = %s", single.Checksum)
+	}
+
+	multi, err := digestS3Source(name, s3MultipartOptions{PartSize: partSize})
+	if err != nil {
+		t.Fatal(err)
+	}
+	composite := sha256.New()
+	for offset := 0; offset < len(data); offset += partSize {
+		end := min(offset+partSize, len(data))
+		part := sha256.Sum256(data[offset:end])
+		composite.Write(part[:])
+	}
+	want := base64.StdEncoding.EncodeToString(composite.Sum(nil)) + "-3"
+	if multi.SHA256 != single.SHA256 {
+		t.Errorf("multipart SHA256 = %s, want %s", multi.SHA256, single.SHA256)
+	}
+	if multi.Checksum != want {
+		t.Errorf("multipart checksum = %s, want %s", multi.Checksum, want)
+	}
+}
+
+func TestSameS3Checksum(t *testing.T) {
+	if !sameS3Checksum("abc=-3", "abc=") {
+		t.Error("expected part count suffix to be ignored")
+	}
+	if sameS3Checksum("abc=-3", "abd=-3") {
+		t.Error("expected different digests to differ")
+	}
+}